@@ -1,22 +1,85 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 
+	"github.com/Nassabiq/gpci-compro-api/internal/app"
 	"github.com/Nassabiq/gpci-compro-api/internal/config"
+	importsmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/imports"
+	productmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/product"
+	rbacmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/rbac"
+	uploadsmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/mailer"
 	"github.com/Nassabiq/gpci-compro-api/internal/queue"
-	"github.com/Nassabiq/gpci-compro-api/internal/utils"
 	"github.com/hibiken/asynq"
 )
 
 func main() {
 	cfg := config.Load()
-	logger := utils.NewLogger(cfg.App.Env)
+	container, cleanup, err := app.New(context.Background(), cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize container: %v\n", err)
+		os.Exit(1)
+	}
+	logger := container.Logger
+
+	dispatcher := queue.NewDispatcher(container.AsynqClient)
+	productMod := productmodule.Provide(container.DB, dispatcher)
+	rbacMod := rbacmodule.Provide(container.DB)
+	importsMod := importsmodule.Provide(container.DB, dispatcher)
+	uploadsMod := uploadsmodule.Provide(container.DB, container.Storage, cfg.Storage.Bucket, cfg.Storage.BasePath, app.UploadsModulePaths(), app.UploadsDerivatives(cfg.Storage), app.UploadsDirect(cfg.Storage), dispatcher)
+
 	redisOpt := asynq.RedisClientOpt{Addr: cfg.Redis.Addr, Password: cfg.Redis.Password, DB: cfg.Redis.DB}
+	scheduler := queue.NewScheduler(redisOpt, cfg.Schedule.Location)
+	if err := queue.RegisterSchedules(scheduler, cfg.Schedule); err != nil {
+		logger.Error("register schedules", "err", err)
+		cleanup()
+		os.Exit(1)
+	}
+	if err := scheduler.Start(); err != nil {
+		logger.Error("scheduler start", "err", err)
+		cleanup()
+		os.Exit(1)
+	}
+
 	server := queue.NewServer(redisOpt, cfg.Asynq.Concurrency, logger)
-	mux := queue.NewMux(&queue.Handlers{Logger: logger})
+	mux := queue.NewMux(&queue.Handlers{
+		Logger: logger,
+		Email:  &queue.EmailHandler{Logger: logger, Mailer: mailer.New(cfg.Mail)},
+		CertificateExpiry: &queue.CertificateExpiryHandler{
+			Logger:       logger,
+			Location:     cfg.Schedule.Location,
+			Certificates: productMod.ProgramCertService,
+		},
+		CertificateReminders: &queue.CertificateReminderHandler{
+			Logger:     logger,
+			Location:   cfg.Schedule.Location,
+			Reminders:  productMod.ProgramCertService,
+			Admins:     rbacMod.Service,
+			Dispatcher: dispatcher,
+			Days:       cfg.Schedule.CertificateReminderDays,
+			AdminRole:  cfg.Schedule.CertificateReminderRole,
+		},
+		CertificateDocuments: &queue.CertificateDocumentHandler{
+			Logger:    logger,
+			Documents: productMod.ProgramCertService,
+			Storage:   uploadsMod.Service,
+		},
+		Imports:          &queue.ImportHandler{Logger: logger, Imports: importsMod.Service},
+		ImageDerivatives: &queue.ImageDerivativesHandler{Logger: logger, Images: uploadsMod.Service},
+		UploadCleanup: &queue.UploadCleanupHandler{
+			Logger:  logger,
+			Uploads: uploadsMod.Service,
+			Grace:   cfg.Storage.OrphanGrace,
+		},
+	})
 	logger.Info("worker started")
-	if err := server.Run(mux); err != nil {
+	err = server.Run(mux)
+	scheduler.Shutdown()
+	cleanup()
+	if err != nil {
 		logger.Error("worker exit", "err", err)
 		os.Exit(1)
 	}
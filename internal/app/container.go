@@ -9,10 +9,10 @@ import (
 
 	"github.com/Nassabiq/gpci-compro-api/internal/config"
 	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	uploadsmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads"
 	"github.com/Nassabiq/gpci-compro-api/internal/utils"
 	"github.com/hibiken/asynq"
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/redis/go-redis/v9"
 )
 
 type Container struct {
@@ -20,7 +20,8 @@ type Container struct {
 	Logger      *slog.Logger
 	DB          *sql.DB
 	AsynqClient *asynq.Client
-	Storage     *minio.Client
+	Redis       *redis.Client
+	Storage     uploadsmodule.Storage
 }
 
 func New(ctx context.Context, cfg *config.Config) (*Container, func(), error) {
@@ -38,14 +39,11 @@ func New(ctx context.Context, cfg *config.Config) (*Container, func(), error) {
 
 	redisOpt := asynq.RedisClientOpt{Addr: cfg.Redis.Addr, Password: cfg.Redis.Password, DB: cfg.Redis.DB}
 	asynqClient := asynq.NewClient(redisOpt)
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr, Password: cfg.Redis.Password, DB: cfg.Redis.DB})
 
-	minioClient, err := minio.New(cfg.Storage.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.Storage.AccessKey, cfg.Storage.SecretKey, ""),
-		Secure: cfg.Storage.UseSSL,
-		Region: cfg.Storage.Region,
-	})
-
+	storage, err := uploadsmodule.NewStorage(cfg.Storage)
 	if err != nil {
+		redisClient.Close()
 		asynqClient.Close()
 		database.Close()
 		return nil, nil, fmt.Errorf("init storage: %w", err)
@@ -56,10 +54,12 @@ func New(ctx context.Context, cfg *config.Config) (*Container, func(), error) {
 		Logger:      logger,
 		DB:          database,
 		AsynqClient: asynqClient,
-		Storage:     minioClient,
+		Redis:       redisClient,
+		Storage:     storage,
 	}
 
 	cleanup := func() {
+		_ = redisClient.Close()
 		_ = asynqClient.Close()
 		_ = database.Close()
 	}
@@ -72,18 +72,11 @@ func New(ctx context.Context, cfg *config.Config) (*Container, func(), error) {
 	return container, cleanup, nil
 }
 
+// ensureBucket prepares the uploads bucket in the way the storage driver
+// needs: creating it in MinIO or its directory on disk.
 func (c *Container) ensureBucket(ctx context.Context, bucket, region string) error {
 	bucketCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	if err := c.Storage.MakeBucket(bucketCtx, bucket, minio.MakeBucketOptions{Region: region}); err != nil {
-		exists, errExists := c.Storage.BucketExists(bucketCtx, bucket)
-		if errExists != nil {
-			return fmt.Errorf("check bucket: %w", errExists)
-		}
-		if !exists {
-			return fmt.Errorf("create bucket: %w", err)
-		}
-	}
-	return nil
+	return c.Storage.EnsureBucket(bucketCtx, bucket, region)
 }
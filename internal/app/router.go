@@ -3,25 +3,43 @@ package app
 import (
 	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/Nassabiq/gpci-compro-api/internal/config"
+	audithandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/audit"
 	authhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/auth"
 	brandhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/brand"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/handler/catalog"
+	certificationhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/certification"
+	companyhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/company"
 	faqhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/faq"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/handler/health"
+	importshandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/imports"
 	mehandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/me"
 	producthandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/product"
+	publichandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/public"
 	rbachandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/rbac"
+	searchhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/search"
+	storagehandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/storage"
 	uploadshandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/uploads"
 	userhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/user"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/middleware"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/audit"
+	authmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/auth"
 	brandmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/brand"
 	catalogmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/catalog"
+	certificationmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/certification"
+	companymodule "github.com/Nassabiq/gpci-compro-api/internal/modules/company"
 	faqmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/faq"
+	importsmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/imports"
 	productmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/product"
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
 	rbacmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/rbac"
+	searchmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/search"
 	uploadsmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads"
+	uploadsdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/repo/filesystem"
 	usersmodule "github.com/Nassabiq/gpci-compro-api/internal/modules/users"
 	"github.com/Nassabiq/gpci-compro-api/internal/queue"
 	"github.com/gofiber/fiber/v2"
@@ -36,6 +54,8 @@ func Setup(container *Container) *fiber.App {
 		ReadTimeout:  cfg.App.ReadTimeout,
 		WriteTimeout: cfg.App.WriteTimeout,
 		IdleTimeout:  cfg.App.IdleTimeout,
+		ProxyHeader:  cfg.App.ProxyHeader,
+		BodyLimit:    fiber.DefaultBodyLimit,
 		ErrorHandler: errorHandler,
 	})
 
@@ -54,35 +74,67 @@ func Setup(container *Container) *fiber.App {
 	}
 	app.Use(fibercors.New(corsConfig))
 
+	auditMod := auditmodule.Provide(container.DB, container.Logger)
+	auditHandler := audithandler.New(auditMod.Service)
+
 	usersMod := usersmodule.Provide(container.DB)
-	auth := authhandler.New(cfg, usersMod.Service)
+	dispatcher := queue.NewDispatcher(container.AsynqClient)
+	authMod := authmodule.Provide(container.DB, container.Redis, dispatcher, cfg)
+	auth := authhandler.New(cfg, usersMod.Service, authMod.Service)
 
 	catalogMod := catalogmodule.Provide(container.DB)
-	catalogHandler := catalog.New(catalogMod.Service)
+	catalogHandler := catalog.New(catalogMod.Service, auditMod.Service)
+
+	certificationMod := certificationmodule.Provide(container.DB)
+	certificationHandler := certificationhandler.New(certificationMod.Service, auditMod.Service)
 
 	brandMod := brandmodule.Provide(container.DB)
-	brandHandler := brandhandler.New(brandMod.Service)
+	brandHandler := brandhandler.New(brandMod.Service, auditMod.Service)
 
-	productMod := productmodule.Provide(container.DB)
-	productHandler := producthandler.New(productMod.Service)
+	productMod := productmodule.Provide(container.DB, dispatcher)
+	productHandler := producthandler.New(productMod.Service, auditMod.Service, container.Logger)
 	productCertHandler := &producthandler.ProductCertificationHandler{
 		Service:        productMod.CertificationService,
 		ProductService: productMod.Service,
+		Audit:          auditMod.Service,
 	}
-	gliCertHandler := producthandler.NewProgramCertificateHandler(productMod.ProgramCertService, "green_label")
-	gtriCertHandler := producthandler.NewProgramCertificateHandler(productMod.ProgramCertService, "green_toll")
+	gliCertHandler := producthandler.NewProgramCertificateHandler(productMod.ProgramCertService, auditMod.Service, container.Logger, "green_label")
+	gtriCertHandler := producthandler.NewProgramCertificateHandler(productMod.ProgramCertService, auditMod.Service, container.Logger, "green_toll")
+	publicHandler := publichandler.New(productMod.Service, brandMod.Service, productMod.ProgramCertService)
 	faqMod := faqmodule.Provide(container.DB)
-	faqHandler := faqhandler.New(faqMod.Service)
-	userHandler := userhandler.New(usersMod.Service)
+	faqHandler := faqhandler.New(faqMod.Service, auditMod.Service)
+	userHandler := userhandler.New(usersMod.Service, authMod.Service, auditMod.Service, container.Logger)
 	rbacMod := rbacmodule.Provide(container.DB)
-	rbacHandler := &rbachandler.RBACHandler{Service: rbacMod.Service}
-	meHandler := mehandler.New(usersMod.Service, rbacMod.Service)
+	rbacHandler := &rbachandler.RBACHandler{Service: rbacMod.Service, Audit: auditMod.Service}
+	meHandler := mehandler.New(usersMod.Service, rbacMod.Service, authMod.Service, auditMod.Service, container.Logger)
+
+	uploadsMod := uploadsmodule.Provide(container.DB, container.Storage, container.Config.Storage.Bucket, container.Config.Storage.BasePath, UploadsModulePaths(), UploadsDerivatives(container.Config.Storage), UploadsDirect(container.Config.Storage), dispatcher)
+	uploadHandler := &uploadshandler.UploadHandler{
+		Service:           uploadsMod.Service,
+		Audit:             auditMod.Service,
+		RedirectDownloads: cfg.Storage.DownloadRedirect,
+		DownloadURLExpiry: cfg.Storage.DownloadURLExpiry,
+	}
+
+	companyMod := companymodule.Provide(container.DB)
+	companyHandler := companyhandler.New(companyMod.Service, uploadsMod.Service, auditMod.Service)
 
-	uploadsMod := uploadsmodule.Provide(container.Storage, container.Config.Storage.Bucket, container.Config.Storage.BasePath, uploadsModulePaths())
-	uploadHandler := &uploadshandler.UploadHandler{Service: uploadsMod.Service}
+	importsMod := importsmodule.Provide(container.DB, dispatcher)
+	importsHandler := importshandler.New(importsMod.Service, auditMod.Service)
+
+	searchMod := searchmodule.Provide(container.DB)
+	searchHandler := searchhandler.New(searchMod.Service)
 
 	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
 
+	// The filesystem driver has no server of its own, so its presigned URLs
+	// point here.
+	if fsStorage, ok := container.Storage.(*filesystem.Repository); ok {
+		storageHandler := storagehandler.New(fsStorage)
+		app.Get("/storage/*", storageHandler.Get)
+		app.Post("/storage/:bucket", storageHandler.Post)
+	}
+
 	api := app.Group("/api")
 
 	api.Get("/ping", func(c *fiber.Ctx) error {
@@ -108,12 +160,37 @@ func Setup(container *Container) *fiber.App {
 
 	api.Post("/auth/register", auth.Register)
 	api.Post("/auth/login", auth.Login)
+	api.Post("/auth/refresh", auth.Refresh)
+
+	// Each flow gets its own budget. Requests that send email are limited
+	// per address, resets per IP.
+	emailLimit := middleware.RateLimitConfig{Max: cfg.RateLimit.AuthMax, Window: cfg.RateLimit.AuthWindow, Key: middleware.EmailKey}
+	resetLimit := middleware.RateLimitConfig{Max: cfg.RateLimit.AuthMax, Window: cfg.RateLimit.AuthWindow}
+	api.Post("/auth/password/forgot", middleware.RateLimit(emailLimit), auth.ForgotPassword)
+	api.Post("/auth/password/reset", middleware.RateLimit(resetLimit), auth.ResetPassword)
+	api.Post("/auth/email/verify", auth.VerifyEmail)
+	api.Post("/auth/email/verify/resend", middleware.RateLimit(emailLimit), auth.ResendVerification)
 
 	api.Get("/health", health.Check)
 
-	jwtCfg := middleware.JWTConfig{Secret: cfg.Auth.JWTSecret}
+	publicGroup := api.Group("/public")
+	publicGroup.Get("/products", publicHandler.ListProducts)
+	publicGroup.Get("/products/:slug", publicHandler.GetProduct)
+	publicGroup.Get("/brands", publicHandler.ListBrands)
+	publicGroup.Get("/gli-certificates", publicHandler.ListCertificates("green_label"))
+	publicGroup.Get("/gtri-certificates", publicHandler.ListCertificates("green_toll"))
+	publicGroup.Get("/files/*", uploadHandler.Download(false))
+	publicGroup.Get("/certificates/verify", middleware.RateLimit(middleware.RateLimitConfig{Max: cfg.RateLimit.VerifyMax, Window: cfg.RateLimit.VerifyWindow}), publicHandler.VerifyCertificate)
+
+	jwtCfg := middleware.JWTConfig{Secret: cfg.Auth.JWTSecret, Validator: authMod.Service}
+	app.Server().HeaderReceived = middleware.LargeBodies(middleware.LargeBodyConfig{
+		Secret: cfg.Auth.JWTSecret,
+		Limit:  UploadsBodyLimit(UploadsModulePaths()),
+		Match:  uploadRoute,
+	})
 
 	authenticated := api.Group("", middleware.JWTAuth(jwtCfg))
+	authenticated.Post("/auth/logout", auth.Logout)
 	authenticated.Get("/profile", meHandler.Profile)
 	authenticated.Put("/profile", meHandler.UpdateProfile)
 	authenticated.Put("/profile/password", meHandler.UpdatePassword)
@@ -133,9 +210,17 @@ func Setup(container *Container) *fiber.App {
 	catalogGroup.Put("/statuses/:id", middleware.RequirePermission(rbacMod.Service, "catalog.statuses.write"), catalogHandler.UpdateStatus)
 	catalogGroup.Delete("/statuses/:id", middleware.RequirePermission(rbacMod.Service, "catalog.statuses.delete"), catalogHandler.DeleteStatus)
 
+	certificationGroup := authenticated.Group("/certifications")
+	certificationGroup.Get("", middleware.RequirePermission(rbacMod.Service, "certifications.read"), certificationHandler.List)
+	certificationGroup.Post("", middleware.RequirePermission(rbacMod.Service, "certifications.write"), certificationHandler.Create)
+	certificationGroup.Get("/:id", middleware.RequirePermission(rbacMod.Service, "certifications.read"), certificationHandler.Get)
+	certificationGroup.Put("/:id", middleware.RequirePermission(rbacMod.Service, "certifications.write"), certificationHandler.Update)
+	certificationGroup.Delete("/:id", middleware.RequirePermission(rbacMod.Service, "certifications.delete"), certificationHandler.Delete)
+
 	productGroup := authenticated.Group("/products")
 	productGroup.Get("", middleware.RequirePermission(rbacMod.Service, "products.read"), productHandler.List)
 	productGroup.Post("", middleware.RequirePermission(rbacMod.Service, "products.write"), productHandler.Create)
+	productGroup.Get("/export", middleware.RequirePermission(rbacMod.Service, "products.read"), productHandler.Export)
 	productGroup.Get(":slug", middleware.RequirePermission(rbacMod.Service, "products.read"), productHandler.Get)
 	productGroup.Put(":slug", middleware.RequirePermission(rbacMod.Service, "products.write"), productHandler.Update)
 	productGroup.Delete(":slug", middleware.RequirePermission(rbacMod.Service, "products.delete"), productHandler.Delete)
@@ -151,6 +236,14 @@ func Setup(container *Container) *fiber.App {
 	brandGroup.Put(":id", middleware.RequirePermission(rbacMod.Service, "brands.write"), brandHandler.UpdateBrand)
 	brandGroup.Delete(":id", middleware.RequirePermission(rbacMod.Service, "brands.delete"), brandHandler.DeleteBrand)
 
+	companyGroup := authenticated.Group("/companies")
+	companyGroup.Get("", middleware.RequirePermission(rbacMod.Service, "companies.read"), companyHandler.List)
+	companyGroup.Post("", middleware.RequirePermission(rbacMod.Service, "companies.write"), companyHandler.Create)
+	companyGroup.Get("/:id", middleware.RequirePermission(rbacMod.Service, "companies.read"), companyHandler.Get)
+	companyGroup.Put("/:id", middleware.RequirePermission(rbacMod.Service, "companies.write"), companyHandler.Update)
+	companyGroup.Delete("/:id", middleware.RequirePermission(rbacMod.Service, "companies.delete"), companyHandler.Delete)
+	companyGroup.Post("/:id/logo", middleware.RequirePermission(rbacMod.Service, "companies.write"), companyHandler.UploadLogo)
+
 	categoryGroup := authenticated.Group("/brand-categories")
 	categoryGroup.Get("", middleware.RequirePermission(rbacMod.Service, "brand.categories.read"), brandHandler.ListBrandCategories)
 	categoryGroup.Post("", middleware.RequirePermission(rbacMod.Service, "brand.categories.write"), brandHandler.CreateBrandCategory)
@@ -160,6 +253,9 @@ func Setup(container *Container) *fiber.App {
 	uploadsGroup := authenticated.Group("/uploads")
 	uploadsGroup.Post("", middleware.RequirePermission(rbacMod.Service, "uploads.create"), uploadHandler.Upload)
 	uploadsGroup.Post("/images", middleware.RequirePermission(rbacMod.Service, "uploads.create"), uploadHandler.Upload)
+	uploadsGroup.Post("/presign", middleware.RequirePermission(rbacMod.Service, "uploads.create"), uploadHandler.Presign)
+	uploadsGroup.Post("/presign/:id/complete", middleware.RequirePermission(rbacMod.Service, "uploads.create"), uploadHandler.Complete)
+	authenticated.Get("/files/*", middleware.RequirePermission(rbacMod.Service, "uploads.documents.read"), uploadHandler.Download(true))
 
 	faqGroup := authenticated.Group("/faqs")
 	faqGroup.Get("", middleware.RequirePermission(rbacMod.Service, "faq.read"), faqHandler.List)
@@ -168,17 +264,46 @@ func Setup(container *Container) *fiber.App {
 	faqGroup.Put("/:id", middleware.RequirePermission(rbacMod.Service, "faq.write"), faqHandler.Update)
 	faqGroup.Delete("/:id", middleware.RequirePermission(rbacMod.Service, "faq.delete"), faqHandler.Delete)
 
+	certificateTransitions := []string{
+		productdomain.TransitionApprove,
+		productdomain.TransitionSuspend,
+		productdomain.TransitionReinstate,
+		productdomain.TransitionRevoke,
+	}
+
 	gliGroup := authenticated.Group("/gli-certificates")
 	gliGroup.Get("", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gliCertHandler.List)
+	gliGroup.Get("/export", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gliCertHandler.Export)
 	gliGroup.Post("", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gliCertHandler.Create)
+	gliGroup.Get("/:slug/:certID", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gliCertHandler.Get)
 	gliGroup.Put("/:slug/:certID", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gliCertHandler.Update)
 	gliGroup.Delete("/:slug/:certID", middleware.RequirePermission(rbacMod.Service, "product.certifications.delete"), gliCertHandler.Delete)
+	gliGroup.Post("/:slug/:certID/renew", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gliCertHandler.Renew)
+	gliGroup.Post("/:slug/:certID/document", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gliCertHandler.GenerateDocument)
+	gliGroup.Get("/:slug/:certID/status-history", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gliCertHandler.StatusHistory)
+	for _, action := range certificateTransitions {
+		gliGroup.Post("/:slug/:certID/"+action, middleware.RequirePermission(rbacMod.Service, "product.certifications.transition"), gliCertHandler.Transition(action))
+	}
 
 	gtriGroup := authenticated.Group("/gtri-certificates")
 	gtriGroup.Get("", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gtriCertHandler.List)
+	gtriGroup.Get("/export", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gtriCertHandler.Export)
 	gtriGroup.Post("", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gtriCertHandler.Create)
+	gtriGroup.Get("/:slug/:certID", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gtriCertHandler.Get)
 	gtriGroup.Put("/:slug/:certID", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gtriCertHandler.Update)
 	gtriGroup.Delete("/:slug/:certID", middleware.RequirePermission(rbacMod.Service, "product.certifications.delete"), gtriCertHandler.Delete)
+	gtriGroup.Post("/:slug/:certID/renew", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gtriCertHandler.Renew)
+	gtriGroup.Post("/:slug/:certID/document", middleware.RequirePermission(rbacMod.Service, "product.certifications.write"), gtriCertHandler.GenerateDocument)
+	gtriGroup.Get("/:slug/:certID/status-history", middleware.RequirePermission(rbacMod.Service, "product.certifications.read"), gtriCertHandler.StatusHistory)
+	for _, action := range certificateTransitions {
+		gtriGroup.Post("/:slug/:certID/"+action, middleware.RequirePermission(rbacMod.Service, "product.certifications.transition"), gtriCertHandler.Transition(action))
+	}
+
+	importsGroup := authenticated.Group("/imports")
+	importsGroup.Post("/products", middleware.RequirePermission(rbacMod.Service, "imports.write"), importsHandler.Create)
+	importsGroup.Get("/:id", middleware.RequirePermission(rbacMod.Service, "imports.read"), importsHandler.Get)
+
+	authenticated.Get("/search", middleware.RequirePermission(rbacMod.Service, "search.read"), searchHandler.Search)
 
 	rbacGroup := authenticated.Group("/rbac")
 	rbacGroup.Post("/roles", middleware.RequirePermission(rbacMod.Service, "rbac.roles.write"), rbacHandler.CreateRole)
@@ -188,6 +313,8 @@ func Setup(container *Container) *fiber.App {
 	rbacGroup.Post("/roles/:role/permissions", middleware.RequirePermission(rbacMod.Service, "rbac.roles.assign"), rbacHandler.AssignPermissionToRole)
 	rbacGroup.Post("/users/:xid/roles", middleware.RequirePermission(rbacMod.Service, "rbac.users.assign_role"), rbacHandler.AssignRoleToUser)
 
+	authenticated.Get("/audit-logs", middleware.RequirePermission(rbacMod.Service, "audit_logs.read"), auditHandler.List)
+
 	usersGroup := api.Group("/users", middleware.JWTAuth(jwtCfg))
 	usersGroup.Get("", middleware.RequirePermission(rbacMod.Service, "users.read"), userHandler.List)
 	usersGroup.Get("/:xid", middleware.RequirePermission(rbacMod.Service, "users.read"), userHandler.Get)
@@ -211,12 +338,87 @@ func errorHandler(c *fiber.Ctx, err error) error {
 	return response.Error(c, http.StatusInternalServerError, "internal_error", err.Error(), nil)
 }
 
-func uploadsModulePaths() map[string]string {
-	return map[string]string{
-		"product":       "images/products",
-		"certification": "images/certifications",
-		"company":       "images/companies",
-		"brand":         "images/brands",
-		"document":      "documents",
+// UploadsModulePaths maps uploads module keys to their storage paths and
+// upload policies. The API and the worker share it so generated files land
+// next to uploaded ones. Other modules get uploadsdomain.DefaultPolicy.
+func UploadsModulePaths() map[string]uploadsdomain.ModuleConfig {
+	images := []string{uploadsdomain.CategoryImages}
+	return map[string]uploadsdomain.ModuleConfig{
+		"product": {Path: "images/products", Policy: uploadsdomain.Policy{
+			Categories: images,
+			MaxSize:    10 << 20,
+			MaxFiles:   10,
+			MaxWidth:   8000,
+			MaxHeight:  8000,
+		}},
+		"certification": {Path: "images/certifications", Policy: uploadsdomain.Policy{
+			Categories: []string{uploadsdomain.CategoryImages, uploadsdomain.CategoryDocuments},
+			MaxSize:    20 << 20,
+			MaxFiles:   5,
+			MaxWidth:   8000,
+			MaxHeight:  8000,
+		}},
+		"company": {Path: "images/companies", Policy: uploadsdomain.Policy{
+			Categories: images,
+			MaxSize:    5 << 20,
+			MaxFiles:   5,
+			MaxWidth:   4000,
+			MaxHeight:  4000,
+		}},
+		// Brands have no image column, so nothing records which brand
+		// images are in use and none of them are cleaned up.
+		"brand": {Path: "images/brands", KeepUnreferenced: true, Policy: uploadsdomain.Policy{
+			Categories: images,
+			MaxSize:    5 << 20,
+			MaxFiles:   5,
+			MaxWidth:   4000,
+			MaxHeight:  4000,
+		}},
+		"document": {Path: "documents", Policy: uploadsdomain.Policy{
+			Categories: []string{uploadsdomain.CategoryDocuments, uploadsdomain.CategoryImages, uploadsdomain.CategoryArchives},
+			MaxSize:    200 << 20,
+			MaxFiles:   10,
+			Private:    true,
+		}},
+	}
+}
+
+// multipartOverhead leaves room for the multipart framing and form fields
+// sent along with an upload.
+const multipartOverhead = 1 << 20
+
+// UploadsBodyLimit sizes the body limit of authenticated upload routes so a
+// single file of the largest size any upload policy accepts reaches the policy
+// checks, which then answer with the structured max_size error. Requests
+// carrying several such files still hit the limit; larger batches should use
+// presigned uploads. Every other route keeps fiber's default limit.
+func UploadsBodyLimit(modules map[string]uploadsdomain.ModuleConfig) int {
+	largest := uploadsdomain.DefaultPolicy.MaxSize
+	for _, module := range modules {
+		largest = max(largest, module.Policy.MaxSize)
 	}
+	return int(largest) + multipartOverhead
+}
+
+// uploadRoute reports whether path accepts file uploads, which may exceed
+// the app-wide body limit up to UploadsBodyLimit.
+func uploadRoute(path string) bool {
+	switch {
+	case path == "/api/uploads", path == "/api/uploads/images", path == "/api/imports/products":
+		return true
+	case strings.HasPrefix(path, "/api/companies/") && strings.HasSuffix(path, "/logo"):
+		return true
+	}
+	return false
+}
+
+// UploadsDerivatives selects the resized copies generated for uploaded
+// images, in the API that queues them and the worker that writes them.
+func UploadsDerivatives(cfg config.StorageConfig) uploadsdomain.DerivativeConfig {
+	return uploadsdomain.DerivativeConfig{Widths: cfg.ImageWidths, WebP: cfg.ImageWebP}
+}
+
+// UploadsDirect limits uploads sent to storage with a presigned URL.
+func UploadsDirect(cfg config.StorageConfig) uploadsdomain.DirectUploadConfig {
+	return uploadsdomain.DirectUploadConfig{MaxSize: cfg.DirectMaxSize, Expiry: cfg.DirectURLExpiry}
 }
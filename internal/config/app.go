@@ -10,6 +10,7 @@ type AppConfig struct {
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
 	ShutdownTimeout time.Duration
+	ProxyHeader     string
 	CORS            CORSConfig
 }
 
@@ -22,6 +23,7 @@ func loadAppConfig() AppConfig {
 		WriteTimeout:    mustDuration("APP_WRITE_TIMEOUT", "15s"),
 		IdleTimeout:     mustDuration("APP_IDLE_TIMEOUT", "60s"),
 		ShutdownTimeout: mustDuration("SHUTDOWN_TIMEOUT", "10s"),
+		ProxyHeader:     getenv("APP_PROXY_HEADER", ""),
 		CORS:            loadCORSConfig(),
 	}
 }
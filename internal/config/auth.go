@@ -3,15 +3,27 @@ package config
 import "time"
 
 type AuthConfig struct {
-	JWTSecret      string
-	JWTExpires     time.Duration
-	RefreshExpires time.Duration
+	JWTSecret        string
+	JWTExpires       time.Duration
+	RefreshExpires   time.Duration
+	PasswordResetTTL time.Duration
+	PasswordResetURL string
+	EmailVerifyTTL   time.Duration
+	EmailVerifyURL   string
+	// RequireVerifiedEmail blocks login until the email address is verified.
+	RequireVerifiedEmail bool
 }
 
 func loadAuthConfig() AuthConfig {
 	return AuthConfig{
-		JWTSecret:      getenv("JWT_SECRET", "changeme"),
-		JWTExpires:     mustDuration("JWT_EXPIRES", "15m"),
-		RefreshExpires: mustDuration("REFRESH_EXPIRES", "168h"),
+		JWTSecret:        getenv("JWT_SECRET", "changeme"),
+		JWTExpires:       mustDuration("JWT_EXPIRES", "15m"),
+		RefreshExpires:   mustDuration("REFRESH_EXPIRES", "168h"),
+		PasswordResetTTL: mustDuration("PASSWORD_RESET_TTL", "1h"),
+		PasswordResetURL: getenv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		EmailVerifyTTL:   mustDuration("EMAIL_VERIFY_TTL", "24h"),
+		EmailVerifyURL:   getenv("EMAIL_VERIFY_URL", "http://localhost:3000/verify-email"),
+
+		RequireVerifiedEmail: mustBool("AUTH_REQUIRE_VERIFIED_EMAIL", false),
 	}
 }
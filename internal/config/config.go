@@ -4,18 +4,22 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App     AppConfig
-	DB      DBConfig
-	Redis   RedisConfig
-	Asynq   AsynqConfig
-	Auth    AuthConfig
-	Storage StorageConfig
+	App       AppConfig
+	DB        DBConfig
+	Redis     RedisConfig
+	Asynq     AsynqConfig
+	Auth      AuthConfig
+	Storage   StorageConfig
+	RateLimit RateLimitConfig
+	Schedule  ScheduleConfig
+	Mail      MailConfig
 }
 
 func mustDuration(key, def string) time.Duration {
@@ -39,6 +43,22 @@ func mustInt(key string, def int) int {
 	return i
 }
 
+func mustIntList(key, def string) []int {
+	var values []int
+	for _, part := range strings.Split(getenv(key, def), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			log.Fatalf("invalid int list for %s: %v", key, err)
+		}
+		values = append(values, i)
+	}
+	return values
+}
+
 func mustBool(key string, def bool) bool {
 	v := getenv(key, "")
 	if v == "" {
@@ -61,11 +81,14 @@ func getenv(key, def string) string {
 func Load() *Config {
 	_ = godotenv.Load()
 	return &Config{
-		App:     loadAppConfig(),
-		DB:      loadDBConfig(),
-		Redis:   loadRedisConfig(),
-		Asynq:   loadAsynqConfig(),
-		Auth:    loadAuthConfig(),
-		Storage: loadStorageConfig(),
+		App:       loadAppConfig(),
+		DB:        loadDBConfig(),
+		Redis:     loadRedisConfig(),
+		Asynq:     loadAsynqConfig(),
+		Auth:      loadAuthConfig(),
+		Storage:   loadStorageConfig(),
+		RateLimit: loadRateLimitConfig(),
+		Schedule:  loadScheduleConfig(),
+		Mail:      loadMailConfig(),
 	}
 }
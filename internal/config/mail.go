@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+type MailConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	FromName   string
+	Encryption string
+	Timeout    time.Duration
+}
+
+func loadMailConfig() MailConfig {
+	return MailConfig{
+		Host:       getenv("MAIL_HOST", "localhost"),
+		Port:       mustInt("MAIL_PORT", 1025),
+		Username:   getenv("MAIL_USERNAME", ""),
+		Password:   getenv("MAIL_PASSWORD", ""),
+		From:       getenv("MAIL_FROM", "no-reply@gpci.local"),
+		FromName:   getenv("MAIL_FROM_NAME", "GPCI"),
+		Encryption: getenv("MAIL_ENCRYPTION", "none"),
+		Timeout:    mustDuration("MAIL_TIMEOUT", "10s"),
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+type RateLimitConfig struct {
+	VerifyMax    int
+	VerifyWindow time.Duration
+	AuthMax      int
+	AuthWindow   time.Duration
+}
+
+func loadRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		VerifyMax:    mustInt("RATE_LIMIT_VERIFY_MAX", 30),
+		VerifyWindow: mustDuration("RATE_LIMIT_VERIFY_WINDOW", "1m"),
+		AuthMax:      mustInt("RATE_LIMIT_AUTH_MAX", 5),
+		AuthWindow:   mustDuration("RATE_LIMIT_AUTH_WINDOW", "15m"),
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+type ScheduleConfig struct {
+	Location                *time.Location
+	CertificateExpiryCron   string
+	CertificateReminderCron string
+	CertificateReminderDays []int
+	// CertificateReminderRole names the role whose members get a copy of
+	// every certificate expiry reminder.
+	CertificateReminderRole string
+	UploadCleanupCron       string
+}
+
+func loadScheduleConfig() ScheduleConfig {
+	return ScheduleConfig{
+		Location:                mustLocation("SCHEDULE_TIMEZONE", "Asia/Jakarta"),
+		CertificateExpiryCron:   getenv("SCHEDULE_CERT_EXPIRY_CRON", "5 0 * * *"),
+		CertificateReminderCron: getenv("SCHEDULE_CERT_REMINDER_CRON", "0 8 * * *"),
+		CertificateReminderDays: mustIntList("CERT_REMINDER_DAYS", "90,30,7"),
+		CertificateReminderRole: getenv("CERT_REMINDER_ROLE", "admin"),
+		UploadCleanupCron:       getenv("SCHEDULE_UPLOAD_CLEANUP_CRON", "30 2 * * *"),
+	}
+}
+
+func mustLocation(key, def string) *time.Location {
+	name := getenv(key, def)
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Fatalf("invalid timezone for %s: %v", key, err)
+	}
+	return loc
+}
@@ -1,6 +1,22 @@
 package config
 
+import "time"
+
+const (
+	StorageDriverMinio      = "minio"
+	StorageDriverFilesystem = "filesystem"
+)
+
 type StorageConfig struct {
+	// Driver selects where uploads are stored: StorageDriverMinio, or
+	// StorageDriverFilesystem for local development, which keeps objects
+	// under Root and serves presigned URLs from FilesystemURL, signed with
+	// FilesystemSecret.
+	Driver           string
+	Root             string
+	FilesystemURL    string
+	FilesystemSecret string
+
 	Endpoint  string
 	AccessKey string
 	SecretKey string
@@ -8,10 +24,30 @@ type StorageConfig struct {
 	Region    string
 	UseSSL    bool
 	BasePath  string
+	// ImageWidths are the derivative widths generated for uploaded images;
+	// ImageWebP adds a WebP copy of each.
+	ImageWidths []int
+	ImageWebP   bool
+	// OrphanGrace is how long an upload may stay unreferenced before the
+	// cleanup task removes it.
+	OrphanGrace time.Duration
+	// DirectMaxSize caps files uploaded with a presigned URL, in bytes, and
+	// DirectURLExpiry is how long such a URL stays valid.
+	DirectMaxSize   int64
+	DirectURLExpiry time.Duration
+	// DownloadRedirect makes the download endpoints redirect to a presigned
+	// URL valid for DownloadURLExpiry instead of streaming files.
+	DownloadRedirect  bool
+	DownloadURLExpiry time.Duration
 }
 
 func loadStorageConfig() StorageConfig {
 	return StorageConfig{
+		Driver:           getenv("STORAGE_DRIVER", StorageDriverMinio),
+		Root:             getenv("STORAGE_ROOT", "./storage"),
+		FilesystemURL:    getenv("STORAGE_FS_URL", "http://localhost:"+getenv("APP_PORT", "8080")+"/storage"),
+		FilesystemSecret: getenv("STORAGE_FS_SECRET", ""),
+
 		Endpoint:  getenv("STORAGE_ENDPOINT", "localhost:9000"),
 		AccessKey: getenv("STORAGE_ACCESS_KEY", "minioadmin"),
 		SecretKey: getenv("STORAGE_SECRET_KEY", "minioadmin"),
@@ -19,5 +55,15 @@ func loadStorageConfig() StorageConfig {
 		Region:    getenv("STORAGE_REGION", ""),
 		UseSSL:    mustBool("STORAGE_USE_SSL", false),
 		BasePath:  getenv("STORAGE_BASE_PATH", "uploads"),
+
+		ImageWidths: mustIntList("STORAGE_IMAGE_WIDTHS", "200,800"),
+		ImageWebP:   mustBool("STORAGE_IMAGE_WEBP", true),
+		OrphanGrace: mustDuration("STORAGE_ORPHAN_GRACE", "72h"),
+
+		DirectMaxSize:   int64(mustInt("STORAGE_DIRECT_MAX_MB", 1024)) << 20,
+		DirectURLExpiry: mustDuration("STORAGE_DIRECT_URL_TTL", "15m"),
+
+		DownloadRedirect:  mustBool("STORAGE_DOWNLOAD_REDIRECT", false),
+		DownloadURLExpiry: mustDuration("STORAGE_DOWNLOAD_URL_TTL", "5m"),
 	}
 }
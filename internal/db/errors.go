@@ -0,0 +1,39 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// IsUniqueViolation reports whether err is a Postgres unique constraint
+// violation. When constraint names are given, only those constraints match.
+func IsUniqueViolation(err error, constraints ...string) bool {
+	return hasPgCode(err, pgUniqueViolation, constraints)
+}
+
+// IsForeignKeyViolation reports whether err is a Postgres foreign key violation.
+func IsForeignKeyViolation(err error, constraints ...string) bool {
+	return hasPgCode(err, pgForeignKeyViolation, constraints)
+}
+
+func hasPgCode(err error, code string, constraints []string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != code {
+		return false
+	}
+	if len(constraints) == 0 {
+		return true
+	}
+	for _, name := range constraints {
+		if pgErr.ConstraintName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func New(service *service.Service) *Handler {
+	return &Handler{Service: service}
+}
+
+// List returns audit entries, newest first. The from/to bounds accept either
+// RFC 3339 timestamps or plain dates; a plain "to" date includes that whole day.
+func (h *Handler) List(c *fiber.Ctx) error {
+	filter := domain.AuditLogFilter{
+		ActorXID:   c.Query("actor"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+	}
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		if size, err := strconv.Atoi(sizeStr); err == nil {
+			filter.PageSize = size
+		}
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseBound(fromStr)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_from", "from must be a date or RFC 3339 timestamp", nil)
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseBound(toStr)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_to", "to must be a date or RFC 3339 timestamp", nil)
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	logs, err := h.Service.List(internalhandler.ContextOrBackground(c), filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "audit_log_list_failed", err.Error(), nil)
+	}
+	meta := fiber.Map{"total": logs.Total, "page": logs.Page, "page_size": logs.PageSize}
+	return response.Success(c, fiber.StatusOK, logs.Items, meta)
+}
+
+func parseBound(raw string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	return t, true, err
+}
@@ -1,20 +1,22 @@
 package auth
 
 import (
-	"time"
+	"errors"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/config"
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	authdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/auth/domain"
+	authservice "github.com/Nassabiq/gpci-compro-api/internal/modules/auth/service"
 	usersservice "github.com/Nassabiq/gpci-compro-api/internal/modules/users/service"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Handler struct {
-	cfg   *config.Config
-	users *usersservice.Service
+	cfg    *config.Config
+	users  *usersservice.Service
+	tokens *authservice.Service
 }
 
 type registerReq struct {
@@ -28,8 +30,35 @@ type loginReq struct {
 	Password string `json:"password"`
 }
 
-func New(cfg *config.Config, users *usersservice.Service) *Handler {
-	return &Handler{cfg: cfg, users: users}
+type refreshReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type forgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type resetPasswordReq struct {
+	Token                string `json:"token" validate:"required"`
+	Password             string `json:"password" validate:"required,min=8"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"required,eqfield=Password"`
+}
+
+type verifyEmailReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type resendVerificationReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type logoutReq struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
+func New(cfg *config.Config, users *usersservice.Service, tokens *authservice.Service) *Handler {
+	return &Handler{cfg: cfg, users: users, tokens: tokens}
 }
 
 func (h *Handler) Register(c *fiber.Ctx) error {
@@ -51,7 +80,10 @@ func (h *Handler) Register(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "user_create_failed", err.Error(), nil)
 	}
-	data := fiber.Map{"id": id, "email": in.Email}
+	// The account exists at this point; a failed verification email can be
+	// retried through the resend endpoint.
+	verificationErr := h.tokens.SendEmailVerification(internalhandler.ContextOrBackground(c), in.Email)
+	data := fiber.Map{"id": id, "email": in.Email, "verification_email_sent": verificationErr == nil}
 	return response.Created(c, data)
 }
 
@@ -74,25 +106,136 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusUnauthorized, "invalid_credentials", "invalid credentials", nil)
 	}
 
-	claims := jwt.MapClaims{
-		"sub":   user.XID,
-		"email": user.Email,
-		"exp":   time.Now().Add(h.cfg.Auth.JWTExpires).Unix(),
-		"iat":   time.Now().Unix(),
-		"iss":   h.cfg.App.Name,
+	if h.cfg.Auth.RequireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return response.Error(c, fiber.StatusForbidden, "email_not_verified", "email address has not been verified", nil)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(h.cfg.Auth.JWTSecret))
 
+	tokens, err := h.tokens.IssueTokens(internalhandler.ContextOrBackground(c), authdomain.Subject{
+		UserID: user.ID,
+		XID:    user.XID,
+		Email:  user.Email,
+	})
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "token_sign_failed", err.Error(), nil)
+		return response.Error(c, fiber.StatusInternalServerError, "token_issue_failed", err.Error(), nil)
+	}
+
+	return response.Success(c, fiber.StatusOK, tokens, nil)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Each refresh token
+// works once; replaying a rotated token signs out every session of that login.
+func (h *Handler) Refresh(c *fiber.Ctx) error {
+	var in refreshReq
+	if err := c.BodyParser(&in); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if in.RefreshToken == "" {
+		return response.Error(c, fiber.StatusBadRequest, "missing_fields", "refresh_token is required", nil)
+	}
+
+	tokens, err := h.tokens.Refresh(internalhandler.ContextOrBackground(c), in.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, authdomain.ErrRefreshTokenReused):
+			return response.Error(c, fiber.StatusUnauthorized, "refresh_token_reused", "refresh token was already used; please log in again", nil)
+		case errors.Is(err, authdomain.ErrRefreshTokenInvalid):
+			return response.Error(c, fiber.StatusUnauthorized, "invalid_refresh_token", "refresh token is invalid or expired", nil)
+		default:
+			return response.Error(c, fiber.StatusInternalServerError, "token_refresh_failed", err.Error(), nil)
+		}
+	}
+	return response.Success(c, fiber.StatusOK, tokens, nil)
+}
+
+// Logout revokes the caller's access token and, if supplied, the refresh token
+// of the same login. {"all": true} ends every session of the user.
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	claims, ok := c.Locals("access_claims").(authdomain.AccessClaims)
+	if !ok || claims.Subject == "" {
+		return response.Error(c, fiber.StatusUnauthorized, "unauthorized", "unauthorized", nil)
+	}
+
+	var in logoutReq
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&in); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+		}
+	}
+
+	if err := h.tokens.Logout(internalhandler.ContextOrBackground(c), claims, in.RefreshToken, in.All); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "logout_failed", err.Error(), nil)
+	}
+	return response.NoContent(c)
+}
+
+// ForgotPassword always answers 202 so callers cannot probe which emails are
+// registered; the reset link is emailed by the worker.
+func (h *Handler) ForgotPassword(c *fiber.Ctx) error {
+	var in forgotPasswordReq
+	if err := c.BodyParser(&in); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &in); err != nil {
+		return err
 	}
 
-	data := fiber.Map{
-		"access_token": signed,
-		"token_type":   "Bearer",
-		"expires_in":   int(h.cfg.Auth.JWTExpires.Seconds()),
+	if err := h.tokens.RequestPasswordReset(internalhandler.ContextOrBackground(c), in.Email); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "password_reset_request_failed", err.Error(), nil)
 	}
+	data := fiber.Map{"message": "if the email is registered, a reset link has been sent"}
+	return response.Success(c, fiber.StatusAccepted, data, nil)
+}
+
+func (h *Handler) ResetPassword(c *fiber.Ctx) error {
+	var in resetPasswordReq
+	if err := c.BodyParser(&in); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &in); err != nil {
+		return err
+	}
+
+	if err := h.tokens.ResetPassword(internalhandler.ContextOrBackground(c), in.Token, in.Password); err != nil {
+		if errors.Is(err, authdomain.ErrResetTokenInvalid) {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_reset_token", "reset token is invalid or expired", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "password_reset_failed", err.Error(), nil)
+	}
+	return response.NoContent(c)
+}
 
-	return response.Success(c, fiber.StatusOK, data, nil)
+func (h *Handler) VerifyEmail(c *fiber.Ctx) error {
+	var in verifyEmailReq
+	if err := c.BodyParser(&in); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &in); err != nil {
+		return err
+	}
+
+	if err := h.tokens.VerifyEmail(internalhandler.ContextOrBackground(c), in.Token); err != nil {
+		if errors.Is(err, authdomain.ErrVerifyTokenInvalid) {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_verification_token", "verification link is invalid or expired", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "email_verify_failed", err.Error(), nil)
+	}
+	return response.Success(c, fiber.StatusOK, fiber.Map{"verified": true}, nil)
+}
+
+// ResendVerification answers 202 whether or not the email belongs to an
+// unverified account.
+func (h *Handler) ResendVerification(c *fiber.Ctx) error {
+	var in resendVerificationReq
+	if err := c.BodyParser(&in); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &in); err != nil {
+		return err
+	}
+
+	if err := h.tokens.SendEmailVerification(internalhandler.ContextOrBackground(c), in.Email); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "verification_email_failed", err.Error(), nil)
+	}
+	data := fiber.Map{"message": "if the email belongs to an unverified account, a verification link has been sent"}
+	return response.Success(c, fiber.StatusAccepted, data, nil)
 }
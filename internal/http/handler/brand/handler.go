@@ -6,17 +6,26 @@ import (
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/brand/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/brand/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+// Entity types brand changes are audited under.
+const (
+	auditEntityCategory = "brand_category"
+	auditEntityBrand    = "brand"
+)
+
 type Handler struct {
 	Service *service.BrandService
+	Audit   *auditservice.Service
 }
 
-func New(service *service.BrandService) *Handler {
-	return &Handler{Service: service}
+func New(service *service.BrandService, audit *auditservice.Service) *Handler {
+	return &Handler{Service: service, Audit: audit}
 }
 
 func (h *Handler) ListBrandCategories(c *fiber.Ctx) error {
@@ -53,6 +62,7 @@ func (h *Handler) CreateBrandCategory(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "brand_category_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityCategory, category.ID, nil, category)
 	return response.Created(c, category)
 }
 
@@ -70,13 +80,23 @@ func (h *Handler) UpdateBrandCategory(c *fiber.Ctx) error {
 		return err
 	}
 
-	category, err := h.Service.UpdateBrandCategory(internalhandler.ContextOrBackground(c), idVal, payload)
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetBrandCategory(ctx, idVal)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "brand_category_not_found", "brand category not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "brand_category_lookup_failed", err.Error(), nil)
+	}
+
+	category, err := h.Service.UpdateBrandCategory(ctx, idVal, payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "brand_category_not_found", "brand category not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "brand_category_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityCategory, idVal, before, category)
 	return response.Success(c, fiber.StatusOK, category, nil)
 }
 
@@ -85,12 +105,21 @@ func (h *Handler) DeleteBrandCategory(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "invalid_brand_category_id", "invalid brand category id", nil)
 	}
-	if err := h.Service.DeleteBrandCategory(internalhandler.ContextOrBackground(c), idVal); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetBrandCategory(ctx, idVal)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "brand_category_not_found", "brand category not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "brand_category_lookup_failed", err.Error(), nil)
+	}
+	if err := h.Service.DeleteBrandCategory(ctx, idVal); err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "brand_category_not_found", "brand category not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "brand_category_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityCategory, idVal, before, nil)
 	return response.NoContent(c)
 }
 
@@ -128,6 +157,7 @@ func (h *Handler) CreateBrand(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "brand_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityBrand, brand.ID, nil, brand)
 	return response.Created(c, brand)
 }
 
@@ -145,13 +175,23 @@ func (h *Handler) UpdateBrand(c *fiber.Ctx) error {
 		return err
 	}
 
-	brand, err := h.Service.UpdateBrand(internalhandler.ContextOrBackground(c), idVal, payload)
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetBrand(ctx, idVal)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "brand_not_found", "brand not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "brand_lookup_failed", err.Error(), nil)
+	}
+
+	brand, err := h.Service.UpdateBrand(ctx, idVal, payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "brand_not_found", "brand not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "brand_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityBrand, idVal, before, brand)
 	return response.Success(c, fiber.StatusOK, brand, nil)
 }
 
@@ -160,11 +200,20 @@ func (h *Handler) DeleteBrand(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "invalid_brand_id", "invalid brand id", nil)
 	}
-	if err := h.Service.DeleteBrand(internalhandler.ContextOrBackground(c), idVal); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetBrand(ctx, idVal)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "brand_not_found", "brand not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "brand_lookup_failed", err.Error(), nil)
+	}
+	if err := h.Service.DeleteBrand(ctx, idVal); err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "brand_not_found", "brand not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "brand_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityBrand, idVal, before, nil)
 	return response.NoContent(c)
 }
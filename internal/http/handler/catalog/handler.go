@@ -6,17 +6,26 @@ import (
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/catalog/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/catalog/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+// Entity types catalog changes are audited under.
+const (
+	auditEntityProgram = "program"
+	auditEntityStatus  = "certification_status"
+)
+
 type Handler struct {
 	Service *service.Service
+	Audit   *auditservice.Service
 }
 
-func New(service *service.Service) *Handler {
-	return &Handler{Service: service}
+func New(service *service.Service, audit *auditservice.Service) *Handler {
+	return &Handler{Service: service, Audit: audit}
 }
 
 func (h *Handler) ListPrograms(c *fiber.Ctx) error {
@@ -41,6 +50,7 @@ func (h *Handler) CreateProgram(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "program_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityProgram, program.ID, nil, program)
 	return response.Created(c, program)
 }
 
@@ -59,6 +69,14 @@ func (h *Handler) UpdateProgram(c *fiber.Ctx) error {
 	}
 
 	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetProgram(ctx, int16(idVal))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "program_not_found", "program not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "program_lookup_failed", err.Error(), nil)
+	}
+
 	program, err := h.Service.UpdateProgram(ctx, int16(idVal), payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -66,6 +84,7 @@ func (h *Handler) UpdateProgram(c *fiber.Ctx) error {
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "program_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityProgram, program.ID, before, program)
 	return response.Success(c, fiber.StatusOK, program, nil)
 }
 
@@ -75,9 +94,17 @@ func (h *Handler) DeleteProgram(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "invalid_program_id", "invalid program id", nil)
 	}
 	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetProgram(ctx, int16(idVal))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "program_not_found", "program not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "program_lookup_failed", err.Error(), nil)
+	}
 	if err := h.Service.DeleteProgram(ctx, int16(idVal)); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "program_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityProgram, before.ID, before, nil)
 	return response.NoContent(c)
 }
 
@@ -103,6 +130,7 @@ func (h *Handler) CreateStatus(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "status_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityStatus, status.ID, nil, status)
 	return response.Created(c, status)
 }
 
@@ -121,6 +149,14 @@ func (h *Handler) UpdateStatus(c *fiber.Ctx) error {
 	}
 
 	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetStatus(ctx, int16(idVal))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "status_not_found", "status not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "status_lookup_failed", err.Error(), nil)
+	}
+
 	status, err := h.Service.UpdateStatus(ctx, int16(idVal), payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -128,6 +164,7 @@ func (h *Handler) UpdateStatus(c *fiber.Ctx) error {
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "status_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityStatus, status.ID, before, status)
 	return response.Success(c, fiber.StatusOK, status, nil)
 }
 
@@ -137,8 +174,16 @@ func (h *Handler) DeleteStatus(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "invalid_status_id", "invalid status id", nil)
 	}
 	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetStatus(ctx, int16(idVal))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "status_not_found", "status not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "status_lookup_failed", err.Error(), nil)
+	}
 	if err := h.Service.DeleteStatus(ctx, int16(idVal)); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "status_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityStatus, before.ID, before, nil)
 	return response.NoContent(c)
 }
@@ -0,0 +1,141 @@
+package certification
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/certification/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/certification/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// auditEntity is the entity type certification changes are audited under.
+const auditEntity = "certification"
+
+type Handler struct {
+	Service *service.CertificationService
+	Audit   *auditservice.Service
+}
+
+func New(service *service.CertificationService, audit *auditservice.Service) *Handler {
+	return &Handler{Service: service, Audit: audit}
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	filter := domain.CertificationFilter{
+		ProgramCode: c.Query("program"),
+		Search:      c.Query("search"),
+	}
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		if size, err := strconv.Atoi(sizeStr); err == nil {
+			filter.PageSize = size
+		}
+	}
+
+	certifications, err := h.Service.ListCertifications(internalhandler.ContextOrBackground(c), filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "certification_list_failed", err.Error(), nil)
+	}
+	meta := fiber.Map{"total": certifications.Total, "page": certifications.Page, "page_size": certifications.PageSize}
+	return response.Success(c, fiber.StatusOK, certifications.Items, meta)
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+
+	certification, err := h.Service.GetCertification(internalhandler.ContextOrBackground(c), idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "certification_lookup_failed")
+	}
+	return response.Success(c, fiber.StatusOK, certification, nil)
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	var payload domain.CertificationPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &payload); err != nil {
+		return err
+	}
+
+	certification, err := h.Service.CreateCertification(internalhandler.ContextOrBackground(c), payload)
+	if err != nil {
+		return h.handleServiceError(c, err, "certification_create_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, certification.ID, nil, certification)
+	return response.Created(c, certification)
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+
+	var payload domain.CertificationPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &payload); err != nil {
+		return err
+	}
+
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetCertification(ctx, idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "certification_lookup_failed")
+	}
+
+	certification, err := h.Service.UpdateCertification(ctx, idVal, payload)
+	if err != nil {
+		return h.handleServiceError(c, err, "certification_update_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, idVal, before, certification)
+	return response.Success(c, fiber.StatusOK, certification, nil)
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetCertification(ctx, idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "certification_lookup_failed")
+	}
+	if err := h.Service.DeleteCertification(ctx, idVal); err != nil {
+		return h.handleServiceError(c, err, "certification_delete_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntity, idVal, before, nil)
+	return response.NoContent(c)
+}
+
+func (h *Handler) handleServiceError(c *fiber.Ctx, err error, code string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return response.Error(c, fiber.StatusNotFound, "certification_not_found", "certification not found", nil)
+	case errors.Is(err, service.ErrCertificationExists):
+		return response.Error(c, fiber.StatusConflict, "certification_exists", "certification name already used in this program", nil)
+	case errors.Is(err, service.ErrCertificationInUse):
+		return response.Error(c, fiber.StatusConflict, "certification_in_use", "certification is referenced by product certificates", nil)
+	case errors.Is(err, service.ErrProgramNotFound):
+		return response.Error(c, fiber.StatusBadRequest, "invalid_program", "program not found", nil)
+	default:
+		return response.Error(c, fiber.StatusInternalServerError, code, err.Error(), nil)
+	}
+}
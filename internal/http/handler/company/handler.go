@@ -0,0 +1,186 @@
+package company
+
+import (
+	"database/sql"
+	"errors"
+	"mime/multipart"
+	"path"
+	"strconv"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/company/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/company/service"
+	uploadsdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	uploadsservice "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// uploadModule is the uploads module key whose path hosts company logos.
+	uploadModule = "company"
+	// auditEntity is the entity type company changes are audited under.
+	auditEntity = "company"
+)
+
+type Handler struct {
+	Service *service.CompanyService
+	Uploads *uploadsservice.Service
+	Audit   *auditservice.Service
+}
+
+func New(service *service.CompanyService, uploads *uploadsservice.Service, audit *auditservice.Service) *Handler {
+	return &Handler{Service: service, Uploads: uploads, Audit: audit}
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	filter := domain.CompanyFilter{Search: c.Query("search")}
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		if size, err := strconv.Atoi(sizeStr); err == nil {
+			filter.PageSize = size
+		}
+	}
+
+	companies, err := h.Service.ListCompanies(internalhandler.ContextOrBackground(c), filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "company_list_failed", err.Error(), nil)
+	}
+	meta := fiber.Map{"total": companies.Total, "page": companies.Page, "page_size": companies.PageSize}
+	return response.Success(c, fiber.StatusOK, companies.Items, meta)
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_company_id", "invalid company id", nil)
+	}
+
+	company, err := h.Service.GetCompany(internalhandler.ContextOrBackground(c), idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "company_lookup_failed")
+	}
+	return response.Success(c, fiber.StatusOK, company, nil)
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	var payload domain.CompanyPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &payload); err != nil {
+		return err
+	}
+
+	company, err := h.Service.CreateCompany(internalhandler.ContextOrBackground(c), payload)
+	if err != nil {
+		return h.handleServiceError(c, err, "company_create_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, company.ID, nil, company)
+	return response.Created(c, company)
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_company_id", "invalid company id", nil)
+	}
+
+	var payload domain.CompanyPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &payload); err != nil {
+		return err
+	}
+
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetCompany(ctx, idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "company_lookup_failed")
+	}
+
+	company, err := h.Service.UpdateCompany(ctx, idVal, payload)
+	if err != nil {
+		return h.handleServiceError(c, err, "company_update_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, idVal, before, company)
+	return response.Success(c, fiber.StatusOK, company, nil)
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_company_id", "invalid company id", nil)
+	}
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetCompany(ctx, idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "company_lookup_failed")
+	}
+	if err := h.Service.DeleteCompany(ctx, idVal); err != nil {
+		return h.handleServiceError(c, err, "company_delete_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntity, idVal, before, nil)
+	return response.NoContent(c)
+}
+
+// UploadLogo stores the multipart "file" under the company uploads path and
+// points companies.image at the stored object.
+func (h *Handler) UploadLogo(c *fiber.Ctx) error {
+	idVal, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_company_id", "invalid company id", nil)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil || fileHeader == nil {
+		return response.Error(c, fiber.StatusBadRequest, "file_required", "file is required", nil)
+	}
+	if err := h.Uploads.CheckFileCategory(uploadModule, fileHeader, uploadsdomain.CategoryImages); err != nil {
+		var policyErr *uploadsdomain.PolicyError
+		if errors.As(err, &policyErr) {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_file", "logo must be an image", nil)
+		}
+		return internalhandler.UploadError(c, err, "upload_failed")
+	}
+
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetCompany(ctx, idVal)
+	if err != nil {
+		return h.handleServiceError(c, err, "company_lookup_failed")
+	}
+
+	actorXID, _ := c.Locals("user_xid").(string)
+	results, err := h.Uploads.UploadFiles(ctx, uploadModule, []*multipart.FileHeader{fileHeader}, actorXID)
+	if err != nil {
+		return internalhandler.UploadError(c, err, "upload_failed")
+	}
+	logo := results[0]
+
+	company, err := h.Service.UpdateCompanyImage(ctx, idVal, path.Join(logo.Directory, logo.Filename))
+	if err != nil {
+		return h.handleServiceError(c, err, "company_update_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, idVal, before, company)
+	return response.Success(c, fiber.StatusOK, company, nil)
+}
+
+func (h *Handler) handleServiceError(c *fiber.Ctx, err error, code string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return response.Error(c, fiber.StatusNotFound, "company_not_found", "company not found", nil)
+	case errors.Is(err, service.ErrCompanySlugExists):
+		return response.Error(c, fiber.StatusConflict, "company_slug_exists", "company slug already used", nil)
+	case errors.Is(err, service.ErrCompanyInUse):
+		return response.Error(c, fiber.StatusConflict, "company_in_use", "company still has products", nil)
+	default:
+		return response.Error(c, fiber.StatusInternalServerError, code, err.Error(), nil)
+	}
+}
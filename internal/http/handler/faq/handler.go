@@ -6,17 +6,23 @@ import (
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/faq/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/faq/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+// auditEntity is the entity type FAQ changes are audited under.
+const auditEntity = "faq"
+
 type Handler struct {
 	Service *service.Service
+	Audit   *auditservice.Service
 }
 
-func New(service *service.Service) *Handler {
-	return &Handler{Service: service}
+func New(service *service.Service, audit *auditservice.Service) *Handler {
+	return &Handler{Service: service, Audit: audit}
 }
 
 func (h *Handler) List(c *fiber.Ctx) error {
@@ -32,11 +38,17 @@ func (h *Handler) List(c *fiber.Ctx) error {
 		}
 	}
 
+	cursor, skipTotal, err := internalhandler.ParseCursor(c)
+	if err != nil {
+		return internalhandler.InvalidCursor(c)
+	}
+	filter.Cursor, filter.SkipTotal = cursor, skipTotal
+
 	faqs, err := h.Service.ListFAQs(internalhandler.ContextOrBackground(c), filter)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "faq_list_failed", err.Error(), nil)
 	}
-	meta := fiber.Map{"total": faqs.Total, "page": faqs.Page, "page_size": faqs.PageSize}
+	meta := internalhandler.PageMeta(faqs.Page, faqs.PageSize, faqs.Total, faqs.NextCursor)
 	return response.Success(c, fiber.StatusOK, faqs.Items, meta)
 }
 
@@ -53,6 +65,7 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "faq_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, faq.ID, nil, faq)
 	return response.Created(c, faq)
 }
 
@@ -89,13 +102,23 @@ func (h *Handler) Update(c *fiber.Ctx) error {
 		return err
 	}
 
-	faq, err := h.Service.UpdateFAQ(internalhandler.ContextOrBackground(c), id, payload)
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetFAQByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "faq_not_found", "faq not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "faq_lookup_failed", err.Error(), nil)
+	}
+
+	faq, err := h.Service.UpdateFAQ(ctx, id, payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "faq_not_found", "faq not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "faq_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, id, before, faq)
 	return response.Success(c, fiber.StatusOK, faq, nil)
 }
 
@@ -105,12 +128,22 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "invalid_faq_id", "invalid FAQ id", nil)
 	}
 
-	if err := h.Service.DeleteFAQ(internalhandler.ContextOrBackground(c), id); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetFAQByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "faq_not_found", "faq not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "faq_lookup_failed", err.Error(), nil)
+	}
+
+	if err := h.Service.DeleteFAQ(ctx, id); err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "faq_not_found", "faq not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "faq_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntity, id, before, nil)
 	return response.NoContent(c)
 }
 
@@ -0,0 +1,87 @@
+package imports
+
+import (
+	"errors"
+	"strconv"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// auditEntity is the entity type import jobs are audited under.
+const auditEntity = "import_job"
+
+type Handler struct {
+	Service *service.Service
+	Audit   *auditservice.Service
+}
+
+func New(service *service.Service, audit *auditservice.Service) *Handler {
+	return &Handler{Service: service, Audit: audit}
+}
+
+// Create accepts a multipart "file" (CSV or XLSX) with optional "mode"
+// (all_or_nothing or partial) and "dry_run" fields, and queues the import.
+func (h *Handler) Create(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil || fileHeader == nil {
+		return response.Error(c, fiber.StatusBadRequest, "file_required", "file is required", nil)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_file", err.Error(), nil)
+	}
+	defer file.Close()
+
+	actorXID, _ := c.Locals("user_xid").(string)
+	dryRun := internalhandler.ParseBoolQuery(c.FormValue("dry_run", c.Query("dry_run")))
+	mode := c.FormValue("mode", c.Query("mode"))
+
+	job, err := h.Service.Create(internalhandler.ContextOrBackground(c), fileHeader.Filename, file, mode, dryRun, actorXID)
+	if err != nil {
+		return h.handleServiceError(c, err, "import_create_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, job.ID, nil, job)
+	return response.Success(c, fiber.StatusAccepted, job, nil)
+}
+
+// Get returns a job's status and, once it finished, its per-row report.
+func (h *Handler) Get(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_import_id", "invalid import id", nil)
+	}
+
+	job, err := h.Service.Get(internalhandler.ContextOrBackground(c), id)
+	if err != nil {
+		return h.handleServiceError(c, err, "import_lookup_failed")
+	}
+	return response.Success(c, fiber.StatusOK, job, nil)
+}
+
+func (h *Handler) handleServiceError(c *fiber.Ctx, err error, code string) error {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound):
+		return response.Error(c, fiber.StatusNotFound, "import_not_found", "import job not found", nil)
+	case errors.Is(err, domain.ErrInvalidMode):
+		return response.Error(c, fiber.StatusBadRequest, "invalid_import_mode", "mode must be all_or_nothing or partial", nil)
+	case errors.Is(err, domain.ErrUnsupportedFormat):
+		return response.Error(c, fiber.StatusBadRequest, "unsupported_import_format", "upload a .csv or .xlsx file", nil)
+	case errors.Is(err, domain.ErrInvalidFile):
+		return response.Error(c, fiber.StatusBadRequest, "invalid_import_file", err.Error(), nil)
+	case errors.Is(err, domain.ErrMissingColumns),
+		errors.Is(err, domain.ErrUnknownColumns):
+		return response.Error(c, fiber.StatusBadRequest, "invalid_import_columns", err.Error(), fiber.Map{"columns": domain.Columns})
+	case errors.Is(err, domain.ErrNoRows):
+		return response.Error(c, fiber.StatusBadRequest, "empty_import_file", "the file has no data rows", nil)
+	case errors.Is(err, domain.ErrTooManyRows):
+		return response.Error(c, fiber.StatusBadRequest, "too_many_import_rows", "split the file into imports of at most "+strconv.Itoa(service.MaxRows)+" rows", nil)
+	default:
+		return response.Error(c, fiber.StatusInternalServerError, code, err.Error(), nil)
+	}
+}
@@ -0,0 +1,31 @@
+package internalhandler
+
+import (
+	"fmt"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/http/middleware"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Audit records a mutation made by the current request, taking the actor,
+// request ID and client IP from the request context. A nil recorder is a
+// no-op so handlers can be built without auditing.
+func Audit(c *fiber.Ctx, recorder *auditservice.Service, action, entityType string, entityID any, before, after any) {
+	if recorder == nil {
+		return
+	}
+	ctx := ContextOrBackground(c)
+	actorXID, _ := c.Locals("user_xid").(string)
+	recorder.Record(ctx, auditdomain.Entry{
+		ActorXID:   actorXID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     before,
+		After:      after,
+		RequestID:  middleware.TraceIDFromContext(ctx),
+		IP:         c.IP(),
+	})
+}
@@ -0,0 +1,175 @@
+package internalhandler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+
+	// exportFlushEvery is how many CSV rows are buffered before they are
+	// flushed to the client.
+	exportFlushEvery = 500
+	exportSheet      = "Sheet1"
+)
+
+// ExportSource yields the rows of an export one at a time. Close is called
+// once streaming ends, whether or not every row was read.
+type ExportSource interface {
+	Next() bool
+	Row() ([]string, error)
+	Err() error
+	Close() error
+}
+
+// ParseExportFormat maps the "format" query value to an export format,
+// defaulting to CSV.
+func ParseExportFormat(value string) (string, bool) {
+	switch value {
+	case "", ExportCSV:
+		return ExportCSV, true
+	case ExportXLSX:
+		return ExportXLSX, true
+	default:
+		return "", false
+	}
+}
+
+// StreamExport sends source as a CSV or XLSX attachment named after name and
+// the current time. Rows are read while the response is written, so the
+// status is already sent when a row fails. The error is then logged and the
+// connection is closed without ending the chunked body, so clients see a
+// failed download rather than a file that looks complete.
+func StreamExport(c *fiber.Ctx, logger *slog.Logger, format, name string, header []string, source ExportSource) error {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102-150405"), format)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	write := writeCSV
+	switch format {
+	case ExportXLSX:
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		write = writeXLSX
+	default:
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+
+	// fasthttp ends the body normally when a body stream writer returns, so
+	// rows go through a pipe whose read error aborts the response instead.
+	reader, writer := io.Pipe()
+	go func() {
+		defer source.Close()
+		out := bufio.NewWriter(writer)
+		err := write(out, header, source)
+		if err == nil {
+			err = out.Flush()
+		}
+		if err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			logger.Error("export failed", "export", name, "format", format, "error", err)
+		}
+		writer.CloseWithError(err)
+	}()
+	c.Context().SetBodyStream(reader, -1)
+	return nil
+}
+
+func writeCSV(w *bufio.Writer, header []string, source ExportSource) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for count := 1; source.Next(); count++ {
+		row, err := source.Row()
+		if err != nil {
+			return err
+		}
+		if err := out.Write(escapeFormulas(row)); err != nil {
+			return err
+		}
+		if count%exportFlushEvery == 0 {
+			out.Flush()
+			if err := out.Error(); err != nil {
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	return source.Err()
+}
+
+// writeXLSX builds the workbook with excelize's stream writer, which spills
+// rows to a temporary file instead of holding them in memory.
+func writeXLSX(w *bufio.Writer, header []string, source ExportSource) error {
+	book := excelize.NewFile()
+	defer book.Close()
+
+	sheet, err := book.NewStreamWriter(exportSheet)
+	if err != nil {
+		return err
+	}
+	if err := sheet.SetRow("A1", stringCells(header)); err != nil {
+		return err
+	}
+	for line := 2; source.Next(); line++ {
+		row, err := source.Row()
+		if err != nil {
+			return err
+		}
+		cell, err := excelize.CoordinatesToCellName(1, line)
+		if err != nil {
+			return err
+		}
+		if err := sheet.SetRow(cell, stringCells(escapeFormulas(row))); err != nil {
+			return err
+		}
+	}
+	if err := source.Err(); err != nil {
+		return err
+	}
+	if err := sheet.Flush(); err != nil {
+		return err
+	}
+	return book.Write(w)
+}
+
+func stringCells(values []string) []any {
+	cells := make([]any, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+	return cells
+}
+
+// formulaPrefixes are the leading characters that make spreadsheet
+// applications evaluate a cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormulas prefixes values that a spreadsheet would evaluate as a
+// formula with a single quote, so user-entered names cannot inject formulas
+// into exported files.
+func escapeFormulas(values []string) []string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+			value = "'" + value
+		}
+		escaped[i] = value
+	}
+	return escaped
+}
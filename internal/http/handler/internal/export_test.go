@@ -0,0 +1,59 @@
+package internalhandler
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEscapeFormulas(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"+62 812 3456", "'+62 812 3456"},
+		{"-1", "'-1"},
+		{"@cmd", "'@cmd"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"Acme = Best", "Acme = Best"},
+		{"GL/2025/001", "GL/2025/001"},
+		{"'quoted", "'quoted"},
+		{"", ""},
+	}
+	values := make([]string, len(tests))
+	for i, tt := range tests {
+		values[i] = tt.value
+	}
+	original := slices.Clone(values)
+
+	got := escapeFormulas(values)
+	for i, tt := range tests {
+		if got[i] != tt.want {
+			t.Errorf("escapeFormulas(%q) = %q, want %q", tt.value, got[i], tt.want)
+		}
+	}
+	if !slices.Equal(values, original) {
+		t.Errorf("escapeFormulas modified its input: %q", values)
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{"", ExportCSV, true},
+		{"csv", ExportCSV, true},
+		{"xlsx", ExportXLSX, true},
+		{"CSV", "", false},
+		{"pdf", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseExportFormat(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseExportFormat(%q) = %q, %v; want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
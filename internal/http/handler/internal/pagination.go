@@ -0,0 +1,49 @@
+package internalhandler
+
+import (
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParseCursor reads the "cursor" and "include_total" queries of a list
+// endpoint. It reports whether counting the total can be skipped: totals are
+// counted in offset mode and skipped in cursor mode unless include_total says
+// otherwise.
+func ParseCursor(c *fiber.Ctx) (*pagination.Cursor, bool, error) {
+	var cursor *pagination.Cursor
+	if value := c.Query("cursor"); value != "" {
+		decoded, err := pagination.Decode(value)
+		if err != nil {
+			return nil, false, err
+		}
+		cursor = &decoded
+	}
+
+	skipTotal := cursor != nil
+	if value := c.Query("include_total"); value != "" {
+		skipTotal = !ParseBoolQuery(value)
+	}
+	return cursor, skipTotal, nil
+}
+
+// InvalidCursor responds to a cursor that ParseCursor rejected.
+func InvalidCursor(c *fiber.Ctx) error {
+	return response.Error(c, fiber.StatusBadRequest, "invalid_cursor", "cursor is not valid; restart from the first page", nil)
+}
+
+// PageMeta builds the meta of a list response. page is left out in cursor
+// mode, total when it was not counted and next_cursor on the last page.
+func PageMeta(page, pageSize int, total *int, nextCursor string) fiber.Map {
+	meta := fiber.Map{"page_size": pageSize}
+	if page > 0 {
+		meta["page"] = page
+	}
+	if total != nil {
+		meta["total"] = *total
+	}
+	if nextCursor != "" {
+		meta["next_cursor"] = nextCursor
+	}
+	return meta
+}
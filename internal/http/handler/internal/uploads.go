@@ -0,0 +1,51 @@
+package internalhandler
+
+import (
+	"errors"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	uploadsdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UploadError answers a failed upload. Policy violations become 413 for the
+// size rule and 422 otherwise, with the failing rule in the details; other
+// unexpected errors are reported with code.
+func UploadError(c *fiber.Ctx, err error, code string) error {
+	var policyErr *uploadsdomain.PolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		status := fiber.StatusUnprocessableEntity
+		if policyErr.Rule == uploadsdomain.RuleMaxSize {
+			status = fiber.StatusRequestEntityTooLarge
+		}
+		details := fiber.Map{"rule": policyErr.Rule, "module": policyErr.Module, "limit": policyErr.Limit, "value": policyErr.Value}
+		if policyErr.File != "" {
+			details["file"] = policyErr.File
+		}
+		return response.Error(c, status, "upload_policy_violation", policyErr.Error(), details)
+	case errors.Is(err, uploadsdomain.ErrEmptyFile),
+		errors.Is(err, uploadsdomain.ErrUnsupportedType),
+		errors.Is(err, uploadsdomain.ErrNoFilesProvided):
+		return response.Error(c, fiber.StatusBadRequest, "invalid_file", err.Error(), nil)
+	case errors.Is(err, uploadsdomain.ErrFileTooLarge):
+		return response.Error(c, fiber.StatusRequestEntityTooLarge, "file_too_large", err.Error(), nil)
+	default:
+		return response.Error(c, fiber.StatusInternalServerError, code, err.Error(), nil)
+	}
+}
+
+// FileHeaders marks a served file so browsers never render it as anything
+// but its declared type, and downloads it instead of showing it unless it is
+// a raster image. Files on the API origin would otherwise run as pages.
+func FileHeaders(c *fiber.Ctx, contentType, key string) {
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml" {
+		return
+	}
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(key)}))
+}
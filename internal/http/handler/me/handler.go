@@ -3,9 +3,13 @@ package me
 import (
 	"context"
 	"database/sql"
+	"log/slog"
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	authservice "github.com/Nassabiq/gpci-compro-api/internal/modules/auth/service"
 	rbacservice "github.com/Nassabiq/gpci-compro-api/internal/modules/rbac/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/users/domain"
 	usersservice "github.com/Nassabiq/gpci-compro-api/internal/modules/users/service"
@@ -13,13 +17,20 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// auditEntity is the entity type profile changes are audited under; it
+// matches the admin user endpoints so a user's history reads as one trail.
+const auditEntity = "user"
+
 type Handler struct {
 	Service *usersservice.Service
 	RBAC    *rbacservice.Service
+	Auth    *authservice.Service
+	Audit   *auditservice.Service
+	Logger  *slog.Logger
 }
 
-func New(service *usersservice.Service, rbac *rbacservice.Service) *Handler {
-	return &Handler{Service: service, RBAC: rbac}
+func New(service *usersservice.Service, rbac *rbacservice.Service, auth *authservice.Service, audit *auditservice.Service, logger *slog.Logger) *Handler {
+	return &Handler{Service: service, RBAC: rbac, Auth: auth, Audit: audit, Logger: logger}
 }
 
 func (h *Handler) Profile(c *fiber.Ctx) error {
@@ -67,11 +78,22 @@ func (h *Handler) UpdateProfile(c *fiber.Ctx) error {
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "user_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, updated.XID, user, updated)
+	// The change is saved at this point; a failed verification email is
+	// reported in meta and can be retried through the resend endpoint.
+	var meta any
+	if updated.Email != user.Email {
+		err := h.Auth.SendEmailVerification(ctx, updated.Email)
+		if err != nil {
+			h.Logger.Error("send verification email", "user_xid", updated.XID, "err", err)
+		}
+		meta = fiber.Map{"verification_email_sent": err == nil}
+	}
 	payloadResp, err := h.profilePayload(ctx, updated)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "profile_load_failed", err.Error(), nil)
 	}
-	return response.Success(c, fiber.StatusOK, payloadResp, nil)
+	return response.Success(c, fiber.StatusOK, payloadResp, meta)
 }
 
 func (h *Handler) UpdatePassword(c *fiber.Ctx) error {
@@ -109,6 +131,11 @@ func (h *Handler) UpdatePassword(c *fiber.Ctx) error {
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "user_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, updated.XID, user, updated)
+	// A new password signs out every session, including this one.
+	if err := h.Auth.RevokeUserSessions(ctx, updated.XID); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "session_revoke_failed", err.Error(), nil)
+	}
 	payloadResp, err := h.profilePayload(ctx, updated)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "profile_load_failed", err.Error(), nil)
@@ -6,6 +6,8 @@ import (
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/service"
 	"github.com/gofiber/fiber/v2"
@@ -14,6 +16,7 @@ import (
 type ProductCertificationHandler struct {
 	Service        *service.ProductCertificationService
 	ProductService *service.ProductService
+	Audit          *auditservice.Service
 }
 
 func (h *ProductCertificationHandler) ListProductCertifications(c *fiber.Ctx) error {
@@ -70,10 +73,15 @@ func (h *ProductCertificationHandler) CreateProductCertification(c *fiber.Ctx) e
 		return response.Error(c, fiber.StatusBadRequest, "validation_failed", "expiry_date must be after issue_date", fiber.Map{"expiry_date": "must be after issue_date"})
 	}
 
-	cert, err := h.Service.CreateProductCertification(internalhandler.ContextOrBackground(c), c.Params("slug"), payload)
+	actorXID, _ := c.Locals("user_xid").(string)
+	cert, err := h.Service.CreateProductCertification(internalhandler.ContextOrBackground(c), c.Params("slug"), payload, actorXID)
 	if err != nil {
+		if err == service.ErrStatusChangeNotAllowed {
+			return response.Error(c, fiber.StatusConflict, "status_change_not_allowed", "new certificates start as pending; use the status transition endpoints to change the status", nil)
+		}
 		return response.Error(c, fiber.StatusInternalServerError, "product_certification_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityCertificate, certificateAuditID(c.Params("slug"), payload.CertificationID), nil, cert)
 	return response.Created(c, cert)
 }
 
@@ -94,13 +102,26 @@ func (h *ProductCertificationHandler) UpdateProductCertification(c *fiber.Ctx) e
 		return response.Error(c, fiber.StatusBadRequest, "validation_failed", "expiry_date must be after issue_date", fiber.Map{"expiry_date": "must be after issue_date"})
 	}
 
-	cert, err := h.Service.UpdateProductCertification(internalhandler.ContextOrBackground(c), c.Params("slug"), certID, payload)
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetProductCertification(ctx, c.Params("slug"), certID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "product_certification_not_found", "product certification not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "product_certification_lookup_failed", err.Error(), nil)
+	}
+
+	cert, err := h.Service.UpdateProductCertification(ctx, c.Params("slug"), certID, payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "product_certification_not_found", "product certification not found", nil)
 		}
+		if err == service.ErrStatusChangeNotAllowed {
+			return response.Error(c, fiber.StatusConflict, "status_change_not_allowed", "use the status transition endpoints to change the certificate status", nil)
+		}
 		return response.Error(c, fiber.StatusInternalServerError, "product_certification_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityCertificate, certificateAuditID(c.Params("slug"), certID), before, cert)
 	return response.Success(c, fiber.StatusOK, cert, nil)
 }
 
@@ -110,12 +131,22 @@ func (h *ProductCertificationHandler) DeleteProductCertification(c *fiber.Ctx) e
 		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
 	}
 
-	if err := h.Service.DeleteProductCertification(internalhandler.ContextOrBackground(c), c.Params("slug"), certID); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetProductCertification(ctx, c.Params("slug"), certID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "product_certification_not_found", "product certification not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "product_certification_lookup_failed", err.Error(), nil)
+	}
+
+	if err := h.Service.DeleteProductCertification(ctx, c.Params("slug"), certID); err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "product_certification_not_found", "product certification not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "product_certification_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityCertificate, certificateAuditID(c.Params("slug"), certID), before, nil)
 
 	return response.NoContent(c)
 }
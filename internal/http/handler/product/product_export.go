@@ -0,0 +1,69 @@
+package product
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// productExportColumns is the header row of product exports. Keep the names
+// and order stable; spreadsheets built on earlier exports rely on them.
+var productExportColumns = []string{
+	"id",
+	"program",
+	"name",
+	"slug",
+	"brand",
+	"brand_category",
+	"company",
+	"is_active",
+	"created_at",
+	"updated_at",
+}
+
+// Export streams every product matching the list filters as CSV or XLSX
+// (?format=csv|xlsx). Paging parameters are ignored.
+func (h *Handler) Export(c *fiber.Ctx) error {
+	format, ok := internalhandler.ParseExportFormat(c.Query("format"))
+	if !ok {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_export_format", "format must be csv or xlsx", nil)
+	}
+
+	// The rows are read after the handler returns, so the query must not be
+	// bound to the request's lifetime.
+	ctx := context.WithoutCancel(internalhandler.ContextOrBackground(c))
+	cursor, err := h.Service.ExportProducts(ctx, productFilter(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "product_export_failed", err.Error(), nil)
+	}
+	return internalhandler.StreamExport(c, h.Logger, format, "products", productExportColumns, productExportSource{cursor})
+}
+
+// productExportSource adapts a product cursor to export rows.
+type productExportSource struct {
+	domain.ProductCursor
+}
+
+func (s productExportSource) Row() ([]string, error) {
+	record, err := s.Product()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		strconv.FormatInt(record.ID, 10),
+		record.ProgramCode,
+		record.Name,
+		record.Slug,
+		record.Brand,
+		record.BrandCategory,
+		record.Company,
+		strconv.FormatBool(record.IsActive),
+		record.CreatedAt.UTC().Format(time.RFC3339),
+		record.UpdatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
@@ -2,32 +2,49 @@ package product
 
 import (
 	"database/sql"
+	"fmt"
+	"log/slog"
 	"strconv"
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+// Entity types product changes are audited under.
+const (
+	auditEntityProduct     = "product"
+	auditEntityCertificate = "product_certification"
+)
+
 type Handler struct {
 	Service *service.ProductService
+	Audit   *auditservice.Service
+	Logger  *slog.Logger
 }
 
-func New(service *service.ProductService) *Handler {
-	return &Handler{Service: service}
+func New(service *service.ProductService, audit *auditservice.Service, logger *slog.Logger) *Handler {
+	return &Handler{Service: service, Audit: audit, Logger: logger}
 }
 
-func (h *Handler) List(c *fiber.Ctx) error {
-	ctx := internalhandler.ContextOrBackground(c)
-	filter := domain.ProductFilter{
+// productFilter reads the list filters shared by List and Export.
+func productFilter(c *fiber.Ctx) domain.ProductFilter {
+	return domain.ProductFilter{
 		ProgramCode:  c.Query("program"),
 		BrandSlug:    c.Query("brand"),
 		CategorySlug: c.Query("category"),
 		Search:       c.Query("search"),
 		IsActiveOnly: internalhandler.ParseBoolQuery(c.Query("is_active")),
 	}
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	ctx := internalhandler.ContextOrBackground(c)
+	filter := productFilter(c)
 
 	if pageStr := c.Query("page"); pageStr != "" {
 		if page, err := strconv.Atoi(pageStr); err == nil {
@@ -40,17 +57,18 @@ func (h *Handler) List(c *fiber.Ctx) error {
 		}
 	}
 
-	result, err := h.Service.ListProducts(ctx, filter)
+	cursor, skipTotal, err := internalhandler.ParseCursor(c)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "product_list_failed", err.Error(), nil)
+		return internalhandler.InvalidCursor(c)
 	}
+	filter.Cursor, filter.SkipTotal = cursor, skipTotal
 
-	meta := fiber.Map{
-		"total":     result.Total,
-		"page":      result.Page,
-		"page_size": result.PageSize,
+	result, err := h.Service.ListProducts(ctx, filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "product_list_failed", err.Error(), nil)
 	}
 
+	meta := internalhandler.PageMeta(result.Page, result.PageSize, result.Total, result.NextCursor)
 	return response.Success(c, fiber.StatusOK, result.Items, meta)
 }
 
@@ -67,6 +85,7 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "product_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityProduct, product.ID, nil, product)
 	return response.Created(c, product)
 }
 
@@ -90,22 +109,48 @@ func (h *Handler) Update(c *fiber.Ctx) error {
 		return err
 	}
 
-	product, err := h.Service.UpdateProduct(internalhandler.ContextOrBackground(c), c.Params("slug"), payload)
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetProductBySlug(ctx, c.Params("slug"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "product_not_found", "product not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "product_lookup_failed", err.Error(), nil)
+	}
+
+	product, err := h.Service.UpdateProduct(ctx, c.Params("slug"), payload)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "product_not_found", "product not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "product_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityProduct, product.ID, before, product)
 	return response.Success(c, fiber.StatusOK, product, nil)
 }
 
 func (h *Handler) Delete(c *fiber.Ctx) error {
-	if err := h.Service.DeleteProduct(internalhandler.ContextOrBackground(c), c.Params("slug")); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.GetProductBySlug(ctx, c.Params("slug"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "product_not_found", "product not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "product_lookup_failed", err.Error(), nil)
+	}
+
+	if err := h.Service.DeleteProduct(ctx, c.Params("slug")); err != nil {
 		if err == sql.ErrNoRows {
 			return response.Error(c, fiber.StatusNotFound, "product_not_found", "product not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "product_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityProduct, before.ID, before, nil)
 	return response.NoContent(c)
 }
+
+// certificateAuditID identifies a product certificate by the product slug and
+// certification ID used in its routes.
+func certificateAuditID(productSlug string, certificationID int64) string {
+	return fmt.Sprintf("%s/%d", productSlug, certificationID)
+}
@@ -0,0 +1,95 @@
+package product
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// certificateExportColumns is the header row of certificate exports. Keep the
+// names and order stable; spreadsheets built on earlier exports rely on them.
+var certificateExportColumns = []string{
+	"id",
+	"program",
+	"certificate_no",
+	"product_name",
+	"product_slug",
+	"brand",
+	"brand_category",
+	"company",
+	"certification",
+	"status",
+	"issue_date",
+	"expiry_date",
+	"created_at",
+	"updated_at",
+}
+
+// Export streams every certificate matching the list filters as CSV or XLSX
+// (?format=csv|xlsx). Paging parameters are ignored.
+func (h *ProgramCertificateHandler) Export(c *fiber.Ctx) error {
+	format, ok := internalhandler.ParseExportFormat(c.Query("format"))
+	if !ok {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_export_format", "format must be csv or xlsx", nil)
+	}
+
+	filter, errs := programCertificateFilter(c)
+	if len(errs) > 0 {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_filter", "invalid filter", errs)
+	}
+
+	// The rows are read after the handler returns, so the query must not be
+	// bound to the request's lifetime.
+	ctx := context.WithoutCancel(internalhandler.ContextOrBackground(c))
+	cursor, err := h.Service.Export(ctx, h.ProgramCode, filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "program_certificate_export_failed", err.Error(), nil)
+	}
+	name := strings.ReplaceAll(h.ProgramCode, "_", "-") + "-certificates"
+	return internalhandler.StreamExport(c, h.Logger, format, name, certificateExportColumns, certificateExportSource{cursor})
+}
+
+// certificateExportSource adapts a certificate cursor to export rows.
+type certificateExportSource struct {
+	domain.ProgramCertificateCursor
+}
+
+func (s certificateExportSource) Row() ([]string, error) {
+	record, err := s.Certificate()
+	if err != nil {
+		return nil, err
+	}
+	status := ""
+	if record.Status != nil {
+		status = record.Status.Code
+	}
+	return []string{
+		strconv.FormatInt(record.ID, 10),
+		record.Program.Code,
+		record.CertificateNo,
+		record.Product.Name,
+		record.Product.Slug,
+		record.Brand.Name,
+		record.Brand.Category.Name,
+		record.Company.Name,
+		record.Certification.Name,
+		status,
+		exportDate(record.IssueDate),
+		exportDate(record.ExpiryDate),
+		record.CreatedAt.UTC().Format(time.RFC3339),
+		record.UpdatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func exportDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
@@ -2,30 +2,50 @@ package product
 
 import (
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	// auditActionRenew is the audit action recorded for certificate renewals.
+	auditActionRenew = "renew"
+	// auditActionGenerateDocument is recorded when a certificate PDF is
+	// requested on demand.
+	auditActionGenerateDocument = "generate_document"
+)
+
 type ProgramCertificateHandler struct {
 	Service     *service.ProgramCertificateService
+	Audit       *auditservice.Service
+	Logger      *slog.Logger
 	ProgramCode string
 }
 
-func NewProgramCertificateHandler(service *service.ProgramCertificateService, programCode string) *ProgramCertificateHandler {
+func NewProgramCertificateHandler(service *service.ProgramCertificateService, audit *auditservice.Service, logger *slog.Logger, programCode string) *ProgramCertificateHandler {
 	return &ProgramCertificateHandler{
 		Service:     service,
+		Audit:       audit,
+		Logger:      logger,
 		ProgramCode: programCode,
 	}
 }
 
 func (h *ProgramCertificateHandler) List(c *fiber.Ctx) error {
-	filter := domain.ProgramCertificateFilter{
-		Search: c.Query("search"),
+	filter, errs := programCertificateFilter(c)
+	if len(errs) > 0 {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_filter", "invalid filter", errs)
 	}
 	if pageStr := c.Query("page"); pageStr != "" {
 		if page, err := strconv.Atoi(pageStr); err == nil {
@@ -38,19 +58,93 @@ func (h *ProgramCertificateHandler) List(c *fiber.Ctx) error {
 		}
 	}
 
-	result, err := h.Service.List(internalhandler.ContextOrBackground(c), h.ProgramCode, filter)
+	cursor, skipTotal, err := internalhandler.ParseCursor(c)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "program_certificate_list_failed", err.Error(), nil)
+		return internalhandler.InvalidCursor(c)
 	}
+	filter.Cursor, filter.SkipTotal = cursor, skipTotal
 
-	meta := fiber.Map{
-		"total":     result.Total,
-		"page":      result.Page,
-		"page_size": result.PageSize,
+	result, err := h.Service.List(internalhandler.ContextOrBackground(c), h.ProgramCode, filter)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_list_failed")
 	}
+
+	meta := internalhandler.PageMeta(result.Page, result.PageSize, result.Total, result.NextCursor)
 	return response.Success(c, fiber.StatusOK, result.Items, meta)
 }
 
+// programCertificateFilter reads the list filters shared by List and Export:
+// search, status, certification_id, brand, brand_category, company,
+// issue_from/issue_to, expiry_from/expiry_to (YYYY-MM-DD), expiring_within
+// (days), sort and order (asc or desc). Problems are returned keyed by query
+// parameter.
+func programCertificateFilter(c *fiber.Ctx) (domain.ProgramCertificateFilter, map[string]string) {
+	filter := domain.ProgramCertificateFilter{
+		Search:            c.Query("search"),
+		StatusCode:        strings.ToLower(c.Query("status")),
+		BrandSlug:         c.Query("brand"),
+		BrandCategorySlug: c.Query("brand_category"),
+		CompanySlug:       c.Query("company"),
+		Sort:              c.Query("sort"),
+	}
+	errs := map[string]string{}
+
+	if idStr := c.Query("certification_id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil || id <= 0 {
+			errs["certification_id"] = "must be a positive integer"
+		}
+		filter.CertificationID = id
+	}
+	for param, target := range map[string]**time.Time{
+		"issue_from":  &filter.IssueFrom,
+		"issue_to":    &filter.IssueTo,
+		"expiry_from": &filter.ExpiryFrom,
+		"expiry_to":   &filter.ExpiryTo,
+	} {
+		if value := c.Query(param); value != "" {
+			date, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				errs[param] = "must be a YYYY-MM-DD date"
+				continue
+			}
+			*target = &date
+		}
+	}
+	if daysStr := c.Query("expiring_within"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 0 || days > domain.MaxExpiringWithinDays {
+			errs["expiring_within"] = fmt.Sprintf("must be a number of days from 0 to %d", domain.MaxExpiringWithinDays)
+		}
+		filter.ExpiringWithinDays = &days
+	}
+	if filter.Sort != "" && !slices.Contains(domain.ProgramCertificateSorts, filter.Sort) {
+		errs["sort"] = "must be one of " + strings.Join(domain.ProgramCertificateSorts, ", ")
+	}
+	switch strings.ToLower(c.Query("order")) {
+	case "", "desc":
+	case "asc":
+		filter.SortAscending = true
+	default:
+		errs["order"] = "must be asc or desc"
+	}
+	return filter, errs
+}
+
+// Get returns a certificate along with its renewal chain.
+func (h *ProgramCertificateHandler) Get(c *fiber.Ctx) error {
+	certID, err := strconv.ParseInt(c.Params("certID"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+
+	record, err := h.Service.GetDetail(internalhandler.ContextOrBackground(c), h.ProgramCode, c.Params("slug"), certID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_lookup_failed")
+	}
+	return response.Success(c, fiber.StatusOK, record, nil)
+}
+
 func (h *ProgramCertificateHandler) Create(c *fiber.Ctx) error {
 	var payload domain.ProgramCertificatePayload
 	if err := c.BodyParser(&payload); err != nil {
@@ -63,12 +157,15 @@ func (h *ProgramCertificateHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "validation_failed", "expiry_date must be after issue_date", fiber.Map{"expiry_date": "must be after issue_date"})
 	}
 
-	record, err := h.Service.Create(internalhandler.ContextOrBackground(c), h.ProgramCode, payload)
+	actorXID, _ := c.Locals("user_xid").(string)
+	record, err := h.Service.Create(internalhandler.ContextOrBackground(c), h.ProgramCode, payload, actorXID)
 	if err != nil {
 		return h.handleServiceError(c, err, "program_certificate_create_failed")
 	}
 
-	return response.Created(c, record)
+	// New certificates are pending; the PDF is queued once they are approved.
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityCertificate, certificateAuditID(record.Product.Slug, record.Certification.ID), nil, record)
+	return response.Success(c, fiber.StatusCreated, record, nil)
 }
 
 func (h *ProgramCertificateHandler) Update(c *fiber.Ctx) error {
@@ -89,10 +186,17 @@ func (h *ProgramCertificateHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "validation_failed", "expiry_date must be after issue_date", fiber.Map{"expiry_date": "must be after issue_date"})
 	}
 
-	record, err := h.Service.Update(internalhandler.ContextOrBackground(c), h.ProgramCode, productSlug, certID, payload)
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.Get(ctx, h.ProgramCode, productSlug, certID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_lookup_failed")
+	}
+
+	record, err := h.Service.Update(ctx, h.ProgramCode, productSlug, certID, payload)
 	if err != nil {
 		return h.handleServiceError(c, err, "program_certificate_update_failed")
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntityCertificate, certificateAuditID(productSlug, certID), before, record)
 	return response.Success(c, fiber.StatusOK, record, nil)
 }
 
@@ -103,12 +207,141 @@ func (h *ProgramCertificateHandler) Delete(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
 	}
 
-	if err := h.Service.Delete(internalhandler.ContextOrBackground(c), h.ProgramCode, productSlug, certID); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.Get(ctx, h.ProgramCode, productSlug, certID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_lookup_failed")
+	}
+
+	if err := h.Service.Delete(ctx, h.ProgramCode, productSlug, certID); err != nil {
 		return h.handleServiceError(c, err, "program_certificate_delete_failed")
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntityCertificate, certificateAuditID(productSlug, certID), before, nil)
 	return response.NoContent(c)
 }
 
+// Transition returns a handler applying the named status transition (see
+// domain.StatusTransitions). The request body must carry a reason.
+func (h *ProgramCertificateHandler) Transition(action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		productSlug := c.Params("slug")
+		certID, err := strconv.ParseInt(c.Params("certID"), 10, 64)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+		}
+
+		var payload domain.StatusTransitionPayload
+		if err := c.BodyParser(&payload); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+		}
+		if err := internalhandler.ValidatePayload(c, &payload); err != nil {
+			return err
+		}
+
+		ctx := internalhandler.ContextOrBackground(c)
+		before, err := h.Service.Get(ctx, h.ProgramCode, productSlug, certID)
+		if err != nil {
+			return h.handleServiceError(c, err, "program_certificate_lookup_failed")
+		}
+
+		actorXID, _ := c.Locals("user_xid").(string)
+		record, err := h.Service.Transition(ctx, h.ProgramCode, productSlug, certID, action, payload.Reason, actorXID)
+		if err != nil {
+			return h.handleServiceError(c, err, "program_certificate_transition_failed")
+		}
+		internalhandler.Audit(c, h.Audit, action, auditEntityCertificate, certificateAuditID(productSlug, certID), before, record)
+		var meta any
+		if action == domain.TransitionApprove {
+			meta = h.queueDocument(c, record)
+		}
+		return response.Success(c, fiber.StatusOK, record, meta)
+	}
+}
+
+// Renew archives the current certificate terms and replaces them with the
+// renewed certificate number and validity window.
+func (h *ProgramCertificateHandler) Renew(c *fiber.Ctx) error {
+	productSlug := c.Params("slug")
+	certID, err := strconv.ParseInt(c.Params("certID"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+
+	var payload domain.CertificateRenewalPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+	if err := internalhandler.ValidatePayload(c, &payload); err != nil {
+		return err
+	}
+	if payload.IssueDate.After(*payload.ExpiryDate) {
+		return response.Error(c, fiber.StatusBadRequest, "validation_failed", "expiry_date must be after issue_date", fiber.Map{"expiry_date": "must be after issue_date"})
+	}
+
+	ctx := internalhandler.ContextOrBackground(c)
+	before, err := h.Service.Get(ctx, h.ProgramCode, productSlug, certID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_lookup_failed")
+	}
+
+	actorXID, _ := c.Locals("user_xid").(string)
+	record, err := h.Service.Renew(ctx, h.ProgramCode, productSlug, certID, payload, actorXID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_renew_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditActionRenew, auditEntityCertificate, certificateAuditID(productSlug, certID), before, record.ProgramCertificate)
+	var meta any
+	if payload.DocumentFile == nil || *payload.DocumentFile == "" {
+		meta = h.queueDocument(c, record.ProgramCertificate)
+	}
+	return response.Success(c, fiber.StatusOK, record, meta)
+}
+
+// GenerateDocument queues a fresh certificate PDF. The worker replaces
+// document_file once the PDF is stored.
+func (h *ProgramCertificateHandler) GenerateDocument(c *fiber.Ctx) error {
+	productSlug := c.Params("slug")
+	certID, err := strconv.ParseInt(c.Params("certID"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+
+	record, err := h.Service.RegenerateDocument(internalhandler.ContextOrBackground(c), h.ProgramCode, productSlug, certID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_document_failed")
+	}
+	internalhandler.Audit(c, h.Audit, auditActionGenerateDocument, auditEntityCertificate, certificateAuditID(productSlug, certID), nil, nil)
+	return response.Success(c, fiber.StatusAccepted, record, fiber.Map{"document_queued": true})
+}
+
+// queueDocument asks the worker to render the certificate PDF and returns the
+// response meta reporting it. A failed enqueue does not fail the request; the
+// document can be generated again through GenerateDocument.
+func (h *ProgramCertificateHandler) queueDocument(c *fiber.Ctx, record domain.ProgramCertificate) any {
+	queued, err := h.Service.QueueDocument(internalhandler.ContextOrBackground(c), record)
+	if err != nil {
+		h.Logger.Error("queue certificate document", "certificate_id", record.ID, "error", err)
+		return fiber.Map{"document_queued": false}
+	}
+	if !queued {
+		return nil
+	}
+	return fiber.Map{"document_queued": true}
+}
+
+func (h *ProgramCertificateHandler) StatusHistory(c *fiber.Ctx) error {
+	certID, err := strconv.ParseInt(c.Params("certID"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_certification_id", "invalid certification id", nil)
+	}
+
+	history, err := h.Service.StatusHistory(internalhandler.ContextOrBackground(c), h.ProgramCode, c.Params("slug"), certID)
+	if err != nil {
+		return h.handleServiceError(c, err, "program_certificate_history_failed")
+	}
+	return response.Success(c, fiber.StatusOK, history, nil)
+}
+
 func (h *ProgramCertificateHandler) handleServiceError(c *fiber.Ctx, err error, code string) error {
 	switch {
 	case err == nil:
@@ -123,6 +356,18 @@ func (h *ProgramCertificateHandler) handleServiceError(c *fiber.Ctx, err error,
 		return response.Error(c, fiber.StatusNotFound, "program_certificate_not_found", "certificate not found", nil)
 	case err == sql.ErrNoRows:
 		return response.Error(c, fiber.StatusNotFound, "program_certificate_not_found", "certificate not found", nil)
+	case err == service.ErrStatusChangeNotAllowed:
+		return response.Error(c, fiber.StatusConflict, "status_change_not_allowed", "use the status transition endpoints to change the certificate status", nil)
+	case err == service.ErrInvalidTransition:
+		return response.Error(c, fiber.StatusConflict, "invalid_status_transition", "transition is not allowed from the current status", nil)
+	case err == service.ErrNotRenewable:
+		return response.Error(c, fiber.StatusConflict, "certificate_not_renewable", "only valid or expired certificates can be renewed", nil)
+	case err == service.ErrDocumentNotIssuable:
+		return response.Error(c, fiber.StatusConflict, "certificate_document_not_issuable", "documents can only be generated for valid certificates", nil)
+	case err == service.ErrUnknownTransition:
+		return response.Error(c, fiber.StatusBadRequest, "unknown_status_transition", "unknown status transition", nil)
+	case err == service.ErrCursorNotSupported:
+		return response.Error(c, fiber.StatusBadRequest, "cursor_not_supported", "cursor paging only works with the default updated_at descending order", nil)
 	default:
 		return response.Error(c, fiber.StatusInternalServerError, code, err.Error(), nil)
 	}
@@ -0,0 +1,151 @@
+package public
+
+import (
+	"database/sql"
+	"strconv"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	branddomain "github.com/Nassabiq/gpci-compro-api/internal/modules/brand/domain"
+	brandservice "github.com/Nassabiq/gpci-compro-api/internal/modules/brand/service"
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	productservice "github.com/Nassabiq/gpci-compro-api/internal/modules/product/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxPublicPageSize = 100
+
+// Handler serves the read-only catalogue used by the company-profile website.
+type Handler struct {
+	Products     *productservice.ProductService
+	Brands       *brandservice.BrandService
+	Certificates *productservice.ProgramCertificateService
+}
+
+func New(products *productservice.ProductService, brands *brandservice.BrandService, certificates *productservice.ProgramCertificateService) *Handler {
+	return &Handler{
+		Products:     products,
+		Brands:       brands,
+		Certificates: certificates,
+	}
+}
+
+func (h *Handler) ListProducts(c *fiber.Ctx) error {
+	filter := productdomain.ProductFilter{
+		ProgramCode:  c.Query("program"),
+		BrandSlug:    c.Query("brand"),
+		CategorySlug: c.Query("category"),
+		Search:       c.Query("search"),
+		IsActiveOnly: true,
+	}
+	filter.Page, filter.PageSize = parsePagination(c)
+	cursor, skipTotal, err := internalhandler.ParseCursor(c)
+	if err != nil {
+		return internalhandler.InvalidCursor(c)
+	}
+	filter.Cursor, filter.SkipTotal = cursor, skipTotal
+
+	result, err := h.Products.ListProducts(internalhandler.ContextOrBackground(c), filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "product_list_failed", err.Error(), nil)
+	}
+
+	items := make([]productdomain.PublicProduct, 0, len(result.Items))
+	for _, product := range result.Items {
+		items = append(items, productdomain.NewPublicProduct(product))
+	}
+
+	meta := internalhandler.PageMeta(result.Page, result.PageSize, result.Total, result.NextCursor)
+	return response.Success(c, fiber.StatusOK, items, meta)
+}
+
+func (h *Handler) GetProduct(c *fiber.Ctx) error {
+	product, err := h.Products.GetProductBySlug(internalhandler.ContextOrBackground(c), c.Params("slug"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.Error(c, fiber.StatusNotFound, "product_not_found", "product not found", nil)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "product_lookup_failed", err.Error(), nil)
+	}
+	if product == nil || !product.IsActive {
+		return response.Error(c, fiber.StatusNotFound, "product_not_found", "product not found", nil)
+	}
+	return response.Success(c, fiber.StatusOK, productdomain.NewPublicProduct(*product), nil)
+}
+
+func (h *Handler) ListBrands(c *fiber.Ctx) error {
+	filter := branddomain.BrandFilter{CategorySlug: c.Query("category")}
+	filter.Page, filter.PageSize = parsePagination(c)
+
+	brands, err := h.Brands.ListBrands(internalhandler.ContextOrBackground(c), filter)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "brand_list_failed", err.Error(), nil)
+	}
+	meta := fiber.Map{"total": brands.Total, "page": brands.Page, "page_size": brands.PageSize}
+	return response.Success(c, fiber.StatusOK, brands.Items, meta)
+}
+
+// ListCertificates returns a handler listing valid certificates of active
+// products for the given program code.
+func (h *Handler) ListCertificates(programCode string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := productdomain.ProgramCertificateFilter{
+			Search:             c.Query("search"),
+			ValidOnly:          true,
+			ActiveProductsOnly: true,
+		}
+		filter.Page, filter.PageSize = parsePagination(c)
+		cursor, skipTotal, err := internalhandler.ParseCursor(c)
+		if err != nil {
+			return internalhandler.InvalidCursor(c)
+		}
+		filter.Cursor, filter.SkipTotal = cursor, skipTotal
+
+		result, err := h.Certificates.List(internalhandler.ContextOrBackground(c), programCode, filter)
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "program_certificate_list_failed", err.Error(), nil)
+		}
+
+		items := make([]productdomain.PublicProgramCertificate, 0, len(result.Items))
+		for _, record := range result.Items {
+			items = append(items, productdomain.NewPublicProgramCertificate(record))
+		}
+
+		meta := internalhandler.PageMeta(result.Page, result.PageSize, result.Total, result.NextCursor)
+		return response.Success(c, fiber.StatusOK, items, meta)
+	}
+}
+
+// VerifyCertificate reports whether a certificate number is genuine and still
+// in force. Unknown numbers yield a 404 so callers can tell them apart from
+// expired or revoked certificates.
+func (h *Handler) VerifyCertificate(c *fiber.Ctx) error {
+	certificateNo := c.Query("certificate_no")
+	if certificateNo == "" {
+		return response.Error(c, fiber.StatusBadRequest, "missing_fields", "certificate_no is required", nil)
+	}
+
+	result, err := h.Certificates.Verify(internalhandler.ContextOrBackground(c), certificateNo)
+	if err != nil {
+		if err == productservice.ErrCertificateNotFound {
+			return response.Error(c, fiber.StatusNotFound, "certificate_not_found", "no certificate matches this number", fiber.Map{"verdict": "not_found"})
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "certificate_verify_failed", err.Error(), nil)
+	}
+	return response.Success(c, fiber.StatusOK, result, nil)
+}
+
+func parsePagination(c *fiber.Ctx) (int, int) {
+	var page, pageSize int
+	if pageStr := c.Query("page"); pageStr != "" {
+		if value, err := strconv.Atoi(pageStr); err == nil {
+			page = value
+		}
+	}
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		if value, err := strconv.Atoi(sizeStr); err == nil {
+			pageSize = min(value, maxPublicPageSize)
+		}
+	}
+	return page, pageSize
+}
@@ -3,12 +3,24 @@ package rbac
 import (
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	rbacservice "github.com/Nassabiq/gpci-compro-api/internal/modules/rbac/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+// Entity types RBAC changes are audited under. Assignments are recorded as
+// the creation of the role/permission or user/role link.
+const (
+	auditEntityRole           = "role"
+	auditEntityPermission     = "permission"
+	auditEntityRolePermission = "role_permission"
+	auditEntityUserRole       = "user_role"
+)
+
 type RBACHandler struct {
 	Service *rbacservice.Service
+	Audit   *auditservice.Service
 }
 
 func (h *RBACHandler) CreateRole(c *fiber.Ctx) error {
@@ -22,7 +34,9 @@ func (h *RBACHandler) CreateRole(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "role_create_failed", err.Error(), nil)
 	}
-	return response.Created(c, fiber.Map{"id": id, "name": in.Name})
+	role := fiber.Map{"id": id, "name": in.Name}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityRole, id, nil, role)
+	return response.Created(c, role)
 }
 
 func (h *RBACHandler) CreatePermission(c *fiber.Ctx) error {
@@ -37,6 +51,7 @@ func (h *RBACHandler) CreatePermission(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "permission_create_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityPermission, id, nil, fiber.Map{"id": id, "key": in.Key, "description": in.Description})
 	return response.Created(c, fiber.Map{"id": id, "key": in.Key})
 }
 
@@ -67,6 +82,7 @@ func (h *RBACHandler) AssignPermissionToRole(c *fiber.Ctx) error {
 	if err := h.Service.AssignPermissionToRole(internalhandler.ContextOrBackground(c), role, in.Permission); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "assign_permission_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityRolePermission, role+":"+in.Permission, nil, fiber.Map{"role": role, "permission": in.Permission})
 	return response.NoContent(c)
 }
 
@@ -81,5 +97,6 @@ func (h *RBACHandler) AssignRoleToUser(c *fiber.Ctx) error {
 	if err := h.Service.AssignRoleToUserByXID(internalhandler.ContextOrBackground(c), userXID, in.Role); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "assign_role_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntityUserRole, userXID+":"+in.Role, nil, fiber.Map{"user_xid": userXID, "role": in.Role})
 	return response.NoContent(c)
 }
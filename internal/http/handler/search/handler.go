@@ -0,0 +1,54 @@
+package search
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/search/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/search/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func New(service *service.Service) *Handler {
+	return &Handler{Service: service}
+}
+
+// Search takes the "q" query, an optional comma separated "types" list and a
+// per-type "limit", and returns ranked hits grouped by type.
+func (h *Handler) Search(c *fiber.Ctx) error {
+	filter := domain.SearchFilter{Query: strings.TrimSpace(c.Query("q"))}
+	if typesStr := c.Query("types"); typesStr != "" {
+		for _, entityType := range strings.Split(typesStr, ",") {
+			if entityType = strings.ToLower(strings.TrimSpace(entityType)); entityType != "" {
+				filter.Types = append(filter.Types, entityType)
+			}
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	result, err := h.Service.Search(internalhandler.ContextOrBackground(c), filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmptyQuery):
+			return response.Error(c, fiber.StatusBadRequest, "empty_search_query", "q is required", nil)
+		case errors.Is(err, domain.ErrQueryTooLong):
+			return response.Error(c, fiber.StatusBadRequest, "search_query_too_long", "q must be at most "+strconv.Itoa(service.MaxQueryLength)+" characters", nil)
+		case errors.Is(err, domain.ErrInvalidType):
+			return response.Error(c, fiber.StatusBadRequest, "invalid_search_type", "types must be any of "+strings.Join(domain.Types, ", "), nil)
+		default:
+			return response.Error(c, fiber.StatusInternalServerError, "search_failed", err.Error(), nil)
+		}
+	}
+	return response.Success(c, fiber.StatusOK, result, nil)
+}
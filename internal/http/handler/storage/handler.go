@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/repo/filesystem"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the presigned URLs of the filesystem storage driver, which
+// has no server of its own. Downloads are GETs of <bucket>/<object key> and
+// uploads are form POSTs to <bucket>; every request must carry the signature
+// it was issued with.
+type Handler struct {
+	Storage *filesystem.Repository
+}
+
+func New(storage *filesystem.Repository) *Handler {
+	return &Handler{Storage: storage}
+}
+
+// Get serves a file behind a URL from PresignGet, honouring Range requests.
+func (h *Handler) Get(c *fiber.Ctx) error {
+	bucket, key := splitPath(c.Params("*"))
+	if !h.Storage.VerifySignature(bucket, key, c.Query("expires"), c.Query("signature")) {
+		return response.Error(c, fiber.StatusForbidden, "invalid_signature", "invalid or expired signature", nil)
+	}
+
+	info, err := h.Storage.StatObject(internalhandler.ContextOrBackground(c), bucket, key)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	name, err := h.Storage.FilePath(bucket, key)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	internalhandler.FileHeaders(c, info.ContentType, key)
+	return c.SendFile(name)
+}
+
+// Post stores the file of a form from PresignPost under the key it was
+// signed for, as long as its size is within the signed range.
+func (h *Handler) Post(c *fiber.Ctx) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_form", "invalid multipart form", nil)
+	}
+	fields := make(map[string]string, len(form.Value))
+	for name, values := range form.Value {
+		if len(values) > 0 {
+			fields[name] = values[0]
+		}
+	}
+
+	bucket := c.Params("bucket")
+	policy, ok := h.Storage.VerifyPost(bucket, fields)
+	if !ok {
+		return response.Error(c, fiber.StatusForbidden, "invalid_signature", "invalid or expired signature", nil)
+	}
+	files := form.File["file"]
+	if len(files) != 1 {
+		return response.Error(c, fiber.StatusBadRequest, "file_required", "file is required", nil)
+	}
+	header := files[0]
+	if header.Size < policy.MinSize || header.Size > policy.MaxSize {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_size", "file size is outside the allowed range", fiber.Map{
+			"min_size": policy.MinSize,
+			"max_size": policy.MaxSize,
+			"size":     header.Size,
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	defer file.Close()
+	if err := h.Storage.PutObject(internalhandler.ContextOrBackground(c), bucket, policy.Key, file, header.Size, policy.ContentType); err != nil {
+		return h.handleError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) handleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrObjectNotFound) {
+		return response.Error(c, fiber.StatusNotFound, "file_not_found", "file not found", nil)
+	}
+	return response.Error(c, fiber.StatusInternalServerError, "storage_failed", err.Error(), nil)
+}
+
+func splitPath(value string) (string, string) {
+	bucket, key, _ := strings.Cut(value, "/")
+	return bucket, key
+}
@@ -2,18 +2,32 @@ package uploads
 
 import (
 	"errors"
+	"fmt"
 	"mime/multipart"
 	"net/http"
+	"path"
+	"strconv"
+	"time"
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/service"
 	"github.com/gofiber/fiber/v2"
 )
 
+// auditEntity is the entity type stored uploads are audited under.
+const auditEntity = "upload"
+
 type UploadHandler struct {
 	Service *service.Service
+	Audit   *auditservice.Service
+	// RedirectDownloads makes Download answer with a presigned storage URL
+	// valid for DownloadURLExpiry instead of streaming the file.
+	RedirectDownloads bool
+	DownloadURLExpiry time.Duration
 }
 
 func (h *UploadHandler) Upload(c *fiber.Ctx) error {
@@ -45,17 +59,148 @@ func (h *UploadHandler) Upload(c *fiber.Ctx) error {
 		module = c.FormValue("module")
 	}
 
-	results, err := h.Service.UploadFiles(internalhandler.ContextOrBackground(c), module, fileHeaders)
+	actorXID, _ := c.Locals("user_xid").(string)
+	results, err := h.Service.UploadFiles(internalhandler.ContextOrBackground(c), module, fileHeaders, actorXID)
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrEmptyFile),
-			errors.Is(err, domain.ErrUnsupportedType),
-			errors.Is(err, domain.ErrNoFilesProvided):
-			return response.Error(c, fiber.StatusBadRequest, "invalid_file", err.Error(), nil)
-		default:
-			return response.Error(c, http.StatusInternalServerError, "upload_failed", err.Error(), nil)
-		}
+		return internalhandler.UploadError(c, err, "upload_failed")
 	}
 
+	for _, result := range results {
+		internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, path.Join(result.Directory, result.Filename), nil, result)
+	}
 	return response.Created(c, fiber.Map{"files": results})
 }
+
+// Presign issues a presigned POST for uploading one file straight to
+// storage. The client posts a multipart form with the returned fields and
+// the file, then calls Complete. A POST policy is used rather than a
+// presigned PUT because it lets storage enforce the allowed size range.
+func (h *UploadHandler) Presign(c *fiber.Ctx) error {
+	var req domain.DirectUploadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_body", "invalid request body", nil)
+	}
+
+	actorXID, _ := c.Locals("user_xid").(string)
+	upload, err := h.Service.PresignUpload(internalhandler.ContextOrBackground(c), req, actorXID)
+	if err != nil {
+		return h.handleServiceError(c, err, "presign_failed")
+	}
+	return response.Created(c, upload)
+}
+
+// Complete registers a direct upload once the client's PUT has finished.
+func (h *UploadHandler) Complete(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid_upload_id", "invalid upload id", nil)
+	}
+
+	actorXID, _ := c.Locals("user_xid").(string)
+	result, err := h.Service.CompleteUpload(internalhandler.ContextOrBackground(c), id, actorXID)
+	if err != nil {
+		return h.handleServiceError(c, err, "upload_failed")
+	}
+
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, path.Join(result.Directory, result.Filename), nil, result)
+	return response.Created(c, fiber.Map{"files": []domain.UploadResult{result}})
+}
+
+func (h *UploadHandler) handleServiceError(c *fiber.Ctx, err error, code string) error {
+	switch {
+	case errors.Is(err, domain.ErrUploadNotFound):
+		return response.Error(c, fiber.StatusNotFound, "upload_not_found", err.Error(), nil)
+	case errors.Is(err, domain.ErrObjectNotFound):
+		return response.Error(c, fiber.StatusConflict, "upload_incomplete", "file has not been uploaded yet", nil)
+	case errors.Is(err, domain.ErrUploadMismatch):
+		return response.Error(c, fiber.StatusUnprocessableEntity, "upload_mismatch", err.Error(), nil)
+	default:
+		return internalhandler.UploadError(c, err, code)
+	}
+}
+
+// Download serves the stored object named by the wildcard path, honouring a
+// single-range Range header and If-None-Match. Files under private modules
+// are only served when allowPrivate is set, i.e. on the route that checks the
+// private-file permission; elsewhere they are reported as missing.
+func (h *UploadHandler) Download(allowPrivate bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := internalhandler.ContextOrBackground(c)
+		key, private, err := h.Service.ResolveDownload(c.Params("*"))
+		if err != nil || (private && !allowPrivate) {
+			return response.Error(c, fiber.StatusNotFound, "file_not_found", "file not found", nil)
+		}
+
+		if h.RedirectDownloads {
+			url, err := h.Service.PresignDownload(ctx, key, h.DownloadURLExpiry)
+			if err != nil {
+				return h.handleDownloadError(c, err)
+			}
+			c.Set(fiber.HeaderCacheControl, "no-store")
+			return c.Redirect(url, fiber.StatusFound)
+		}
+
+		info, err := h.Service.StatFile(ctx, key)
+		if err != nil {
+			return h.handleDownloadError(c, err)
+		}
+
+		if private {
+			c.Set(fiber.HeaderCacheControl, "private, no-cache")
+		} else {
+			c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+		}
+		c.Set(fiber.HeaderAcceptRanges, "bytes")
+		if info.ETag != "" {
+			etag := strconv.Quote(info.ETag)
+			c.Set(fiber.HeaderETag, etag)
+			if c.Get(fiber.HeaderIfNoneMatch) == etag {
+				return c.SendStatus(fiber.StatusNotModified)
+			}
+		}
+		if !info.LastModified.IsZero() {
+			c.Set(fiber.HeaderLastModified, info.LastModified.UTC().Format(http.TimeFormat))
+		}
+		contentType := info.ContentType
+		if contentType == "" {
+			contentType = fiber.MIMEOctetStream
+		}
+		c.Set(fiber.HeaderContentType, contentType)
+		internalhandler.FileHeaders(c, contentType, key)
+
+		offset, length := int64(0), info.Size
+		if c.Get(fiber.HeaderRange) != "" && info.Size > 0 {
+			ranges, err := c.Range(int(info.Size))
+			if errors.Is(err, fiber.ErrRangeUnsatisfiable) {
+				c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", info.Size))
+				return response.Error(c, fiber.StatusRequestedRangeNotSatisfiable, "invalid_range", "requested range not satisfiable", nil)
+			}
+			// Malformed headers and multiple ranges are answered with the
+			// whole file, which RFC 9110 allows.
+			if err == nil && ranges.Type == "bytes" && len(ranges.Ranges) == 1 {
+				r := ranges.Ranges[0]
+				offset, length = int64(r.Start), int64(r.End-r.Start+1)
+				c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End, info.Size))
+				c.Status(fiber.StatusPartialContent)
+			}
+		}
+
+		if length == 0 {
+			return c.Send(nil)
+		}
+		reader, err := h.Service.OpenFile(ctx, key, offset, length)
+		if err != nil {
+			return h.handleDownloadError(c, err)
+		}
+		c.Context().SetBodyStream(reader, int(length))
+		return nil
+	}
+}
+
+func (h *UploadHandler) handleDownloadError(c *fiber.Ctx, err error) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	if errors.Is(err, domain.ErrObjectNotFound) {
+		return response.Error(c, fiber.StatusNotFound, "file_not_found", "file not found", nil)
+	}
+	return response.Error(c, fiber.StatusInternalServerError, "download_failed", err.Error(), nil)
+}
@@ -3,21 +3,31 @@ package user
 import (
 	"database/sql"
 	"errors"
+	"log/slog"
 
 	internalhandler "github.com/Nassabiq/gpci-compro-api/internal/http/handler/internal"
 	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	auditdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+	auditservice "github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+	authservice "github.com/Nassabiq/gpci-compro-api/internal/modules/auth/service"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/users/domain"
 	usersservice "github.com/Nassabiq/gpci-compro-api/internal/modules/users/service"
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// auditEntity is the entity type user changes are audited under.
+const auditEntity = "user"
+
 type Handler struct {
 	Service *usersservice.Service
+	Auth    *authservice.Service
+	Audit   *auditservice.Service
+	Logger  *slog.Logger
 }
 
-func New(service *usersservice.Service) *Handler {
-	return &Handler{Service: service}
+func New(service *usersservice.Service, auth *authservice.Service, audit *auditservice.Service, logger *slog.Logger) *Handler {
+	return &Handler{Service: service, Auth: auth, Audit: audit, Logger: logger}
 }
 
 func (h *Handler) List(c *fiber.Ctx) error {
@@ -72,6 +82,7 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusInternalServerError, "user_lookup_failed", "user not found after creation", nil)
 	}
 
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionCreate, auditEntity, user.XID, nil, user)
 	return response.Created(c, user)
 }
 
@@ -115,16 +126,46 @@ func (h *Handler) Update(c *fiber.Ctx) error {
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "user_update_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionUpdate, auditEntity, user.XID, existing, user)
 
-	return response.Success(c, fiber.StatusOK, user, nil)
+	// The change is saved at this point; a failed verification email is
+	// reported in meta and can be retried through the resend endpoint.
+	var meta any
+	if user.Email != existing.Email {
+		err := h.Auth.SendEmailVerification(ctx, user.Email)
+		if err != nil {
+			h.Logger.Error("send verification email", "user_xid", user.XID, "err", err)
+		}
+		meta = fiber.Map{"verification_email_sent": err == nil}
+	}
+
+	// Deactivation and password resets end the user's existing sessions so
+	// that neither old tokens nor a later reactivation revive them.
+	if passwordHash != nil || (existing.IsActive && !isActive) {
+		if err := h.Auth.RevokeUserSessions(ctx, user.XID); err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "session_revoke_failed", err.Error(), nil)
+		}
+	}
+
+	return response.Success(c, fiber.StatusOK, user, meta)
 }
 
 func (h *Handler) Delete(c *fiber.Ctx) error {
-	if err := h.Service.Delete(internalhandler.ContextOrBackground(c), c.Params("xid")); err != nil {
+	ctx := internalhandler.ContextOrBackground(c)
+	existing, err := h.Service.FindByXID(ctx, c.Params("xid"))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "users_lookup_failed", err.Error(), nil)
+	}
+	if existing == nil {
+		return response.Error(c, fiber.StatusNotFound, "user_not_found", "user not found", nil)
+	}
+
+	if err := h.Service.Delete(ctx, existing.XID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return response.Error(c, fiber.StatusNotFound, "user_not_found", "user not found", nil)
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "user_delete_failed", err.Error(), nil)
 	}
+	internalhandler.Audit(c, h.Audit, auditdomain.ActionDelete, auditEntity, existing.XID, existing, nil)
 	return response.NoContent(c)
 }
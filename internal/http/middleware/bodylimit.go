@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"bytes"
+
+	"github.com/valyala/fasthttp"
+)
+
+// LargeBodyConfig selects the requests allowed past the app-wide body limit.
+type LargeBodyConfig struct {
+	// Secret verifies the bearer token; only authenticated requests qualify.
+	Secret string
+	// Limit is the body limit applied to matching requests.
+	Limit int
+	// Match reports whether a POST to path may carry a large body.
+	Match func(path string) bool
+}
+
+// LargeBodies raises the body limit for authenticated POSTs to the routes
+// cfg.Match accepts. It runs as the server's HeaderReceived hook, before the
+// body is read, so every other request keeps the app-wide limit and cannot
+// make the server buffer a large body. The route's own middleware still
+// authenticates and authorizes the request.
+func LargeBodies(cfg LargeBodyConfig) func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	return func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		if !header.IsPost() {
+			return fasthttp.RequestConfig{}
+		}
+		path := header.RequestURI()
+		if i := bytes.IndexByte(path, '?'); i >= 0 {
+			path = path[:i]
+		}
+		if !cfg.Match(string(path)) {
+			return fasthttp.RequestConfig{}
+		}
+		if _, err := parseAccessToken(cfg.Secret, string(header.Peek("Authorization"))); err != nil {
+			return fasthttp.RequestConfig{}
+		}
+		return fasthttp.RequestConfig{MaxRequestBodySize: cfg.Limit}
+	}
+}
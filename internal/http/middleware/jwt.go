@@ -1,36 +1,31 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"time"
 
+	authdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/auth/domain"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-type JWTConfig struct{ Secret string }
+// TokenValidator decides whether a signature-valid access token is still
+// honoured server-side.
+type TokenValidator interface {
+	ValidateAccessToken(ctx context.Context, claims authdomain.AccessClaims) error
+}
+
+type JWTConfig struct {
+	Secret    string
+	Validator TokenValidator
+}
 
 func JWTAuth(cfg JWTConfig) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		auth := c.Get("Authorization")
-
-		if len(auth) < 8 || auth[:7] != "Bearer " {
-			return fiber.NewError(fiber.StatusUnauthorized, "missing bearer token")
-		}
-
-		tokenStr := auth[7:]
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) { return []byte(cfg.Secret), nil })
-
-		if err != nil || !token.Valid {
-			return fiber.NewError(fiber.StatusUnauthorized, "invalid token")
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			return fiber.NewError(fiber.StatusUnauthorized, "invalid claims")
-		}
-
-		if exp, ok := claims["exp"].(float64); ok && time.Now().Unix() > int64(exp) {
-			return fiber.NewError(fiber.StatusUnauthorized, "token expired")
+		claims, err := parseAccessToken(cfg.Secret, c.Get("Authorization"))
+		if err != nil {
+			return err
 		}
 
 		// Support numeric or string subject identifiers
@@ -41,6 +36,65 @@ func JWTAuth(cfg JWTConfig) fiber.Handler {
 			c.Locals("user_xid", uxid)
 		}
 
+		access := accessClaims(claims)
+		if cfg.Validator != nil {
+			ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+			defer cancel()
+
+			if err := cfg.Validator.ValidateAccessToken(ctx, access); err != nil {
+				if errors.Is(err, authdomain.ErrTokenRevoked) {
+					return fiber.NewError(fiber.StatusUnauthorized, "token revoked")
+				}
+				return fiber.NewError(fiber.StatusServiceUnavailable, "token validation unavailable")
+			}
+		}
+		c.Locals("access_claims", access)
+
 		return c.Next()
 	}
 }
+
+func accessClaims(claims jwt.MapClaims) authdomain.AccessClaims {
+	access := authdomain.AccessClaims{}
+	access.Subject, _ = claims["sub"].(string)
+	access.JTI, _ = claims["jti"].(string)
+	if ver, ok := claims["ver"].(float64); ok {
+		access.Version = int(ver)
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		access.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return access
+}
+
+// parseAccessToken checks the signature, expiry and kind of the bearer token
+// in an Authorization header. It does not consult the TokenValidator.
+func parseAccessToken(secret, auth string) (jwt.MapClaims, error) {
+	if len(auth) < 8 || auth[:7] != "Bearer " {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "missing bearer token")
+	}
+
+	tokenStr := auth[7:]
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) { return []byte(secret), nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil || !token.Valid {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid claims")
+	}
+
+	if exp, ok := claims["exp"].(float64); ok && time.Now().Unix() > int64(exp) {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "token expired")
+	}
+
+	// Purpose-specific tokens (e.g. email verification) share the signing
+	// secret but are never valid as access tokens.
+	if _, ok := claims["typ"]; ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid token")
+	}
+	return claims, nil
+}
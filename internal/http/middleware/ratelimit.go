@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+type RateLimitConfig struct {
+	Max    int
+	Window time.Duration
+	// Key names the budget a request counts against; the client IP when nil.
+	Key func(c *fiber.Ctx) string
+}
+
+// RateLimit throttles requests using an in-memory sliding window. Every call
+// returns a limiter with its own counters, so routes sharing a config do not
+// share a budget.
+func RateLimit(cfg RateLimitConfig) fiber.Handler {
+	key := cfg.Key
+	if key == nil {
+		key = func(c *fiber.Ctx) string {
+			return c.IP()
+		}
+	}
+	return limiter.New(limiter.Config{
+		Max:               cfg.Max,
+		Expiration:        cfg.Window,
+		LimiterMiddleware: limiter.SlidingWindow{},
+		KeyGenerator:      key,
+		LimitReached: func(c *fiber.Ctx) error {
+			return response.Error(c, fiber.StatusTooManyRequests, "rate_limited", "too many requests, please try again later", nil)
+		},
+	})
+}
+
+// EmailKey keys a rate limit on the "email" field of the request body, so
+// clients behind one address do not throttle each other while each mailbox
+// still gets a bounded number of messages. Requests without an email fall
+// back to the client IP.
+func EmailKey(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email" form:"email"`
+	}
+	if err := c.BodyParser(&body); err == nil {
+		if email := strings.ToLower(strings.TrimSpace(body.Email)); email != "" {
+			return "email:" + email
+		}
+	}
+	return "ip:" + c.IP()
+}
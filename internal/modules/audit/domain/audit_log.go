@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+type AuditLog struct {
+	ID         int64           `json:"id"`
+	ActorXID   string          `json:"actor_xid,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Entry describes a single mutation to record. Before and After hold the
+// entity state on either side of the change and are stored as JSON; leave
+// Before nil on create and After nil on delete.
+type Entry struct {
+	ActorXID   string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     any
+	After      any
+	RequestID  string
+	IP         string
+}
+
+type AuditLogFilter struct {
+	ActorXID   string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int `json:"-" validate:"omitempty,min=1"`
+	PageSize   int `json:"-" validate:"omitempty,min=1,max=100"`
+}
+
+type AuditLogListResponse struct {
+	Items    []AuditLog `json:"items"`
+	Total    int        `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+)
+
+type AuditLogRepository struct {
+	DB *sql.DB
+}
+
+func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
+	return &AuditLogRepository{DB: db}
+}
+
+func (r *AuditLogRepository) CreateAuditLog(ctx context.Context, log domain.AuditLog) error {
+	const query = `
+INSERT INTO public.audit_logs (actor_xid, action, entity_type, entity_id, before_data, after_data, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		nullString(log.ActorXID),
+		log.Action,
+		log.EntityType,
+		log.EntityID,
+		nullJSON(log.Before),
+		nullJSON(log.After),
+		nullString(log.RequestID),
+		nullString(log.IP),
+	)
+	return err
+}
+
+func (r *AuditLogRepository) ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, int, error) {
+	var (
+		args       []any
+		conditions []string
+	)
+	addCondition := func(clause string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.ActorXID != "" {
+		addCondition("actor_xid = $%d", filter.ActorXID)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "\nWHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM public.audit_logs`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.PageSize
+	offset := 0
+	if filter.Page > 0 && filter.PageSize > 0 {
+		offset = (filter.Page - 1) * filter.PageSize
+	}
+	if limit > 0 && offset >= total {
+		return []domain.AuditLog{}, total, nil
+	}
+
+	query := `
+SELECT id, actor_xid, action, entity_type, entity_id, before_data, after_data, request_id, ip, created_at
+FROM public.audit_logs` + where + `
+ORDER BY created_at DESC, id DESC`
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf("\nLIMIT $%d", len(args))
+		if offset > 0 {
+			args = append(args, offset)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	logs := []domain.AuditLog{}
+	for rows.Next() {
+		var (
+			log       domain.AuditLog
+			actorXID  sql.NullString
+			requestID sql.NullString
+			ip        sql.NullString
+			before    []byte
+			after     []byte
+		)
+		if err := rows.Scan(
+			&log.ID,
+			&actorXID,
+			&log.Action,
+			&log.EntityType,
+			&log.EntityID,
+			&before,
+			&after,
+			&requestID,
+			&ip,
+			&log.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		log.ActorXID = actorXID.String
+		log.RequestID = requestID.String
+		log.IP = ip.String
+		log.Before = before
+		log.After = after
+		logs = append(logs, log)
+	}
+	return logs, total, rows.Err()
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/audit/domain"
+)
+
+type Repository interface {
+	CreateAuditLog(ctx context.Context, log domain.AuditLog) error
+	ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, int, error)
+}
+
+type Service struct {
+	repo   Repository
+	logger *slog.Logger
+}
+
+func NewService(repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{repo: repo, logger: logger}
+}
+
+// Record stores an audit entry. Auditing is best effort: the mutation it
+// describes has already been committed, so failures are logged rather than
+// returned to the caller.
+func (s *Service) Record(ctx context.Context, entry domain.Entry) {
+	if s == nil {
+		return
+	}
+
+	log := domain.AuditLog{
+		ActorXID:   entry.ActorXID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+	}
+	var err error
+	if log.Before, err = marshalState(entry.Before); err != nil {
+		s.logger.Error("audit marshal failed", "entity_type", entry.EntityType, "entity_id", entry.EntityID, "error", err)
+		return
+	}
+	if log.After, err = marshalState(entry.After); err != nil {
+		s.logger.Error("audit marshal failed", "entity_type", entry.EntityType, "entity_id", entry.EntityID, "error", err)
+		return
+	}
+
+	// Keep the write alive even if the client has already gone away.
+	if err := s.repo.CreateAuditLog(context.WithoutCancel(ctx), log); err != nil {
+		s.logger.Error("audit record failed",
+			"action", entry.Action,
+			"entity_type", entry.EntityType,
+			"entity_id", entry.EntityID,
+			"request_id", entry.RequestID,
+			"error", err,
+		)
+	}
+}
+
+func (s *Service) List(ctx context.Context, filter domain.AuditLogFilter) (domain.AuditLogListResponse, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+
+	items, total, err := s.repo.ListAuditLogs(ctx, filter)
+	if err != nil {
+		return domain.AuditLogListResponse{}, err
+	}
+	return domain.AuditLogListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func marshalState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(state)
+	if err != nil || string(raw) == "null" {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"database/sql"
+	"log/slog"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/audit/repo/postgres"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/audit/service"
+)
+
+type Module struct {
+	Repository *postgres.AuditLogRepository
+	Service    *service.Service
+}
+
+func Provide(db *sql.DB, logger *slog.Logger) *Module {
+	repo := postgres.NewAuditLogRepository(db)
+	return &Module{
+		Repository: repo,
+		Service:    service.NewService(repo, logger),
+	}
+}
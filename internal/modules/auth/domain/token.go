@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrResetTokenInvalid   = errors.New("password reset token is invalid or expired")
+	ErrVerifyTokenInvalid  = errors.New("email verification token is invalid or expired")
+)
+
+// TokenPair is returned by login and refresh.
+type TokenPair struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
+
+// Subject identifies the user a token is issued for.
+type Subject struct {
+	UserID  int64
+	XID     string
+	Email   string
+	Version int
+}
+
+// AccessClaims are the access-token claims checked on every request.
+type AccessClaims struct {
+	Subject   string
+	JTI       string
+	Version   int
+	ExpiresAt time.Time
+}
+
+// UserTokenState is what decides whether a user's tokens are still honoured.
+type UserTokenState struct {
+	UserID   int64
+	Version  int
+	IsActive bool
+	Deleted  bool
+}
+
+// NewRefreshToken is a refresh token row about to be stored.
+type NewRefreshToken struct {
+	UserID    int64
+	TokenHash string
+	FamilyID  string
+	ExpiresAt time.Time
+}
+
+// Account is the user record looked up by email for password resets and
+// verification emails.
+type Account struct {
+	UserID        int64
+	XID           string
+	Name          string
+	Email         string
+	IsActive      bool
+	EmailVerified bool
+}
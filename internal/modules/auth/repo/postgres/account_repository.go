@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/auth/domain"
+)
+
+// AccountRepository looks up accounts for the email flows and stores their
+// password reset tokens and email verification state.
+type AccountRepository struct {
+	DB *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) *AccountRepository {
+	return &AccountRepository{DB: db}
+}
+
+func (r *AccountRepository) FindAccountByEmail(ctx context.Context, email string) (domain.Account, error) {
+	const query = `
+SELECT id, xid, name, email, is_active, email_verified_at IS NOT NULL
+FROM users
+WHERE email = $1 AND deleted_at IS NULL
+LIMIT 1`
+
+	var account domain.Account
+	err := r.DB.QueryRowContext(ctx, query, email).Scan(
+		&account.UserID, &account.XID, &account.Name, &account.Email, &account.IsActive, &account.EmailVerified,
+	)
+	return account, err
+}
+
+// MarkEmailVerified verifies the user's email, provided it is still the
+// address the verification link was sent to. Verifying twice is a no-op.
+func (r *AccountRepository) MarkEmailVerified(ctx context.Context, userXID, email string) (bool, error) {
+	const query = `
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE xid = $1 AND email = $2 AND deleted_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, userXID, email)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// CreatePasswordResetToken stores a new reset token and retires the user's
+// older unused ones, so only the latest email works.
+func (r *AccountRepository) CreatePasswordResetToken(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	const retireQuery = `
+UPDATE public.password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL`
+
+	const insertQuery = `
+INSERT INTO public.password_reset_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)`
+
+	return db.WithTx(r.DB, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, retireQuery, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, insertQuery, userID, tokenHash, expiresAt)
+		return err
+	})
+}
+
+// ResetPassword consumes the reset token and, in the same transaction, sets
+// the new password hash and ends every session of the user.
+func (r *AccountRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) error {
+	const consumeQuery = `
+UPDATE public.password_reset_tokens prt
+SET used_at = $2
+FROM users u
+WHERE prt.token_hash = $1
+  AND prt.used_at IS NULL
+  AND prt.expires_at > $2
+  AND u.id = prt.user_id
+  AND u.is_active = TRUE
+  AND u.deleted_at IS NULL
+RETURNING prt.user_id`
+
+	const passwordQuery = `
+UPDATE users
+SET password = $2, token_version = token_version + 1, updated_at = NOW()
+WHERE id = $1`
+
+	const revokeQuery = `
+UPDATE public.refresh_tokens
+SET revoked_at = $2
+WHERE user_id = $1 AND revoked_at IS NULL`
+
+	return db.WithTx(r.DB, func(tx *sql.Tx) error {
+		var userID int64
+		err := tx.QueryRowContext(ctx, consumeQuery, tokenHash, now).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return domain.ErrResetTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, passwordQuery, userID, passwordHash); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, revokeQuery, userID, now)
+		return err
+	})
+}
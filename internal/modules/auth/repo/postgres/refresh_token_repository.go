@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/auth/domain"
+)
+
+type RefreshTokenRepository struct {
+	DB *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{DB: db}
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token domain.NewRefreshToken) error {
+	const query = `
+INSERT INTO public.refresh_tokens (user_id, token_hash, family_id, expires_at)
+VALUES ($1, $2, $3, $4)`
+
+	_, err := r.DB.ExecContext(ctx, query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt)
+	return err
+}
+
+// RotateRefreshToken swaps the token identified by oldHash for next within one
+// transaction. next.UserID and next.FamilyID are taken from the old token.
+// Presenting a token that was already rotated revokes its whole family and
+// returns domain.ErrRefreshTokenReused; a token revoked by logout is simply
+// invalid.
+func (r *RefreshTokenRepository) RotateRefreshToken(ctx context.Context, oldHash string, next domain.NewRefreshToken, now time.Time) (domain.Subject, error) {
+	const lookupQuery = `
+SELECT rt.id, rt.family_id, rt.expires_at, rt.revoked_at, rt.replaced_by IS NOT NULL, u.id, u.xid, u.email, u.token_version, u.is_active, u.deleted_at
+FROM public.refresh_tokens rt
+JOIN users u ON u.id = rt.user_id
+WHERE rt.token_hash = $1
+FOR UPDATE OF rt`
+
+	const revokeFamilyQuery = `
+UPDATE public.refresh_tokens
+SET revoked_at = $2
+WHERE family_id = $1 AND revoked_at IS NULL`
+
+	const insertQuery = `
+INSERT INTO public.refresh_tokens (user_id, token_hash, family_id, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id`
+
+	const retireQuery = `
+UPDATE public.refresh_tokens
+SET revoked_at = $2, replaced_by = $3
+WHERE id = $1`
+
+	var (
+		subject domain.Subject
+		reused  bool
+	)
+	err := db.WithTx(r.DB, func(tx *sql.Tx) error {
+		var (
+			tokenID   int64
+			familyID  string
+			expiresAt time.Time
+			revokedAt sql.NullTime
+			rotated   bool
+			isActive  bool
+			deletedAt sql.NullTime
+		)
+		err := tx.QueryRowContext(ctx, lookupQuery, oldHash).Scan(
+			&tokenID, &familyID, &expiresAt, &revokedAt, &rotated,
+			&subject.UserID, &subject.XID, &subject.Email, &subject.Version, &isActive, &deletedAt,
+		)
+		if err == sql.ErrNoRows {
+			return domain.ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		if rotated {
+			reused = true
+			_, err := tx.ExecContext(ctx, revokeFamilyQuery, familyID, now)
+			return err
+		}
+		if revokedAt.Valid || !expiresAt.After(now) || !isActive || deletedAt.Valid {
+			return domain.ErrRefreshTokenInvalid
+		}
+
+		var nextID int64
+		if err := tx.QueryRowContext(ctx, insertQuery, subject.UserID, next.TokenHash, familyID, next.ExpiresAt).Scan(&nextID); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, retireQuery, tokenID, now, nextID)
+		return err
+	})
+	if err != nil {
+		return domain.Subject{}, err
+	}
+	if reused {
+		return domain.Subject{}, domain.ErrRefreshTokenReused
+	}
+	return subject, nil
+}
+
+// RevokeRefreshTokenFamily revokes the login the token belongs to, provided it
+// is owned by userXID.
+func (r *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, tokenHash, userXID string) error {
+	const query = `
+UPDATE public.refresh_tokens
+SET revoked_at = NOW()
+WHERE revoked_at IS NULL
+  AND family_id = (
+      SELECT rt.family_id
+      FROM public.refresh_tokens rt
+      JOIN users u ON u.id = rt.user_id
+      WHERE rt.token_hash = $1 AND u.xid = $2
+  )`
+
+	_, err := r.DB.ExecContext(ctx, query, tokenHash, userXID)
+	return err
+}
+
+func (r *RefreshTokenRepository) GetUserTokenState(ctx context.Context, userXID string) (domain.UserTokenState, error) {
+	const query = `
+SELECT id, token_version, is_active, deleted_at IS NOT NULL
+FROM users
+WHERE xid = $1`
+
+	var state domain.UserTokenState
+	err := r.DB.QueryRowContext(ctx, query, userXID).Scan(&state.UserID, &state.Version, &state.IsActive, &state.Deleted)
+	return state, err
+}
+
+// RevokeUserSessions bumps the user's token version, which invalidates every
+// access token issued so far, and revokes all of their refresh tokens.
+func (r *RefreshTokenRepository) RevokeUserSessions(ctx context.Context, userXID string) error {
+	const bumpQuery = `
+UPDATE users
+SET token_version = token_version + 1
+WHERE xid = $1
+RETURNING id`
+
+	const revokeQuery = `
+UPDATE public.refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL`
+
+	return db.WithTx(r.DB, func(tx *sql.Tx) error {
+		var userID int64
+		if err := tx.QueryRowContext(ctx, bumpQuery, userXID).Scan(&userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, revokeQuery, userID)
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const denylistPrefix = "auth:denylist:"
+
+// Denylist remembers revoked access-token IDs until the tokens would have
+// expired anyway.
+type Denylist struct {
+	Client *goredis.Client
+}
+
+func NewDenylist(client *goredis.Client) *Denylist {
+	return &Denylist{Client: client}
+}
+
+func (d *Denylist) Deny(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return d.Client.Set(ctx, denylistPrefix+jti, 1, ttl).Err()
+}
+
+func (d *Denylist) IsDenied(ctx context.Context, jti string) (bool, error) {
+	n, err := d.Client.Exists(ctx, denylistPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
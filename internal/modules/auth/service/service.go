@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/config"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/auth/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/mailer"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// emailVerifyTokenType marks verification JWTs; access tokens carry no "typ"
+// claim and JWTAuth rejects any token that does.
+const emailVerifyTokenType = "email_verify"
+
+type Repository interface {
+	CreateRefreshToken(ctx context.Context, token domain.NewRefreshToken) error
+	RotateRefreshToken(ctx context.Context, oldHash string, next domain.NewRefreshToken, now time.Time) (domain.Subject, error)
+	RevokeRefreshTokenFamily(ctx context.Context, tokenHash, userXID string) error
+	GetUserTokenState(ctx context.Context, userXID string) (domain.UserTokenState, error)
+	RevokeUserSessions(ctx context.Context, userXID string) error
+}
+
+// AccountRepository backs the password reset and email verification flows.
+type AccountRepository interface {
+	FindAccountByEmail(ctx context.Context, email string) (domain.Account, error)
+	MarkEmailVerified(ctx context.Context, userXID, email string) (bool, error)
+	CreatePasswordResetToken(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) error
+}
+
+// EmailDispatcher queues outgoing email for the worker; queue.Dispatcher
+// implements it. SendUrgentEmail is for mail the user is waiting on.
+type EmailDispatcher interface {
+	SendEmail(ctx context.Context, msg mailer.Message) error
+	SendUrgentEmail(ctx context.Context, msg mailer.Message) error
+}
+
+type Denylist interface {
+	Deny(ctx context.Context, jti string, ttl time.Duration) error
+	IsDenied(ctx context.Context, jti string) (bool, error)
+}
+
+type Service struct {
+	repo     Repository
+	accounts AccountRepository
+	denylist Denylist
+	emails   EmailDispatcher
+	cfg      config.AuthConfig
+	issuer   string
+}
+
+func New(repo Repository, accounts AccountRepository, denylist Denylist, emails EmailDispatcher, cfg config.AuthConfig, issuer string) *Service {
+	return &Service{repo: repo, accounts: accounts, denylist: denylist, emails: emails, cfg: cfg, issuer: issuer}
+}
+
+// IssueTokens starts a new refresh token family for a fresh login.
+func (s *Service) IssueTokens(ctx context.Context, subject domain.Subject) (domain.TokenPair, error) {
+	state, err := s.repo.GetUserTokenState(ctx, subject.XID)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	subject.Version = state.Version
+
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	err = s.repo.CreateRefreshToken(ctx, domain.NewRefreshToken{
+		UserID:    subject.UserID,
+		TokenHash: refreshHash,
+		FamilyID:  uuid.NewString(),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshExpires),
+	})
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	return s.tokenPair(subject, refreshToken)
+}
+
+// Refresh rotates a refresh token: the presented token is retired and a new
+// one from the same family is returned with a fresh access token.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (domain.TokenPair, error) {
+	refreshToken = strings.TrimSpace(refreshToken)
+	if refreshToken == "" {
+		return domain.TokenPair{}, domain.ErrRefreshTokenInvalid
+	}
+
+	nextToken, nextHash, err := newRefreshToken()
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	now := time.Now()
+	subject, err := s.repo.RotateRefreshToken(ctx, hashToken(refreshToken), domain.NewRefreshToken{
+		TokenHash: nextHash,
+		ExpiresAt: now.Add(s.cfg.RefreshExpires),
+	}, now)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	return s.tokenPair(subject, nextToken)
+}
+
+// ValidateAccessToken rejects access tokens that were logged out, issued
+// before the user's sessions were revoked, or that belong to a deactivated or
+// deleted user.
+func (s *Service) ValidateAccessToken(ctx context.Context, claims domain.AccessClaims) error {
+	if claims.JTI != "" {
+		denied, err := s.denylist.IsDenied(ctx, claims.JTI)
+		if err != nil {
+			return err
+		}
+		if denied {
+			return domain.ErrTokenRevoked
+		}
+	}
+
+	state, err := s.repo.GetUserTokenState(ctx, claims.Subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if !state.IsActive || state.Deleted || state.Version != claims.Version {
+		return domain.ErrTokenRevoked
+	}
+	return nil
+}
+
+// Logout revokes the presented access token and, when given, the refresh
+// token family of the same login. With all set, every session of the user
+// ends.
+func (s *Service) Logout(ctx context.Context, claims domain.AccessClaims, refreshToken string, all bool) error {
+	if claims.JTI != "" {
+		if err := s.denylist.Deny(ctx, claims.JTI, time.Until(claims.ExpiresAt)); err != nil {
+			return err
+		}
+	}
+	if refreshToken = strings.TrimSpace(refreshToken); refreshToken != "" {
+		if err := s.repo.RevokeRefreshTokenFamily(ctx, hashToken(refreshToken), claims.Subject); err != nil {
+			return err
+		}
+	}
+	if all {
+		return s.repo.RevokeUserSessions(ctx, claims.Subject)
+	}
+	return nil
+}
+
+// RevokeUserSessions ends every session of the user, e.g. after a password
+// change or deactivation.
+func (s *Service) RevokeUserSessions(ctx context.Context, userXID string) error {
+	return s.repo.RevokeUserSessions(ctx, userXID)
+}
+
+// RequestPasswordReset emails a single-use reset link. Unknown and inactive
+// accounts are ignored silently so the endpoint does not reveal which emails
+// are registered.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+
+	account, err := s.accounts.FindAccountByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !account.IsActive {
+		return nil
+	}
+
+	token, tokenHash, err := newRefreshToken()
+	if err != nil {
+		return err
+	}
+	if err := s.accounts.CreatePasswordResetToken(ctx, account.UserID, tokenHash, time.Now().Add(s.cfg.PasswordResetTTL)); err != nil {
+		return err
+	}
+
+	link := s.cfg.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nWe received a request to reset your password. Open the link below within %s to choose a new one:\n\n%s\n\nIf you did not request this, you can ignore this email; your password stays unchanged.\n",
+		account.Name, s.cfg.PasswordResetTTL, link,
+	)
+	return s.emails.SendUrgentEmail(ctx, mailer.Message{
+		To:      []string{account.Email},
+		Subject: "Reset your password",
+		Body:    body,
+	})
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out everywhere.
+func (s *Service) ResetPassword(ctx context.Context, token, password string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return domain.ErrResetTokenInvalid
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.accounts.ResetPassword(ctx, hashToken(token), string(hash), time.Now())
+}
+
+// SendEmailVerification emails a signed verification link for the account's
+// current address. Unknown, inactive and already verified accounts are
+// skipped silently.
+func (s *Service) SendEmailVerification(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+
+	account, err := s.accounts.FindAccountByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !account.IsActive || account.EmailVerified {
+		return nil
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"typ":   emailVerifyTokenType,
+		"sub":   account.XID,
+		"email": account.Email,
+		"exp":   now.Add(s.cfg.EmailVerifyTTL).Unix(),
+		"iat":   now.Unix(),
+		"iss":   s.issuer,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.JWTSecret))
+	if err != nil {
+		return err
+	}
+
+	link := s.cfg.EmailVerifyURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nPlease confirm that %s is your email address by opening the link below within %s:\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+		account.Name, account.Email, s.cfg.EmailVerifyTTL, link,
+	)
+	return s.emails.SendEmail(ctx, mailer.Message{
+		To:      []string{account.Email},
+		Subject: "Verify your email address",
+		Body:    body,
+	})
+}
+
+// VerifyEmail confirms the address a verification link was issued for. Links
+// for an address the user has since changed are rejected.
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	parsed, err := jwt.Parse(strings.TrimSpace(token), func(t *jwt.Token) (interface{}, error) { return []byte(s.cfg.JWTSecret), nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !parsed.Valid {
+		return domain.ErrVerifyTokenInvalid
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != emailVerifyTokenType {
+		return domain.ErrVerifyTokenInvalid
+	}
+	xid, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	if xid == "" || email == "" {
+		return domain.ErrVerifyTokenInvalid
+	}
+
+	verified, err := s.accounts.MarkEmailVerified(ctx, xid, email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return domain.ErrVerifyTokenInvalid
+	}
+	return nil
+}
+
+func (s *Service) tokenPair(subject domain.Subject, refreshToken string) (domain.TokenPair, error) {
+	accessToken, err := s.signAccessToken(subject)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	return domain.TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.cfg.JWTExpires.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(s.cfg.RefreshExpires.Seconds()),
+	}, nil
+}
+
+func (s *Service) signAccessToken(subject domain.Subject) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":   subject.XID,
+		"email": subject.Email,
+		"jti":   uuid.NewString(),
+		"ver":   subject.Version,
+		"exp":   now.Add(s.cfg.JWTExpires).Unix(),
+		"iat":   now.Unix(),
+		"iss":   s.issuer,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.cfg.JWTSecret))
+}
+
+// newRefreshToken returns an opaque random token and the hash that is stored.
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/config"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/auth/repo/postgres"
+	authredis "github.com/Nassabiq/gpci-compro-api/internal/modules/auth/repo/redis"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/auth/service"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type Module struct {
+	Repository *postgres.RefreshTokenRepository
+	Service    *service.Service
+}
+
+func Provide(db *sql.DB, redisClient *goredis.Client, emails service.EmailDispatcher, cfg *config.Config) *Module {
+	repo := postgres.NewRefreshTokenRepository(db)
+	return &Module{
+		Repository: repo,
+		Service:    service.New(repo, postgres.NewAccountRepository(db), authredis.NewDenylist(redisClient), emails, cfg.Auth, cfg.App.Name),
+	}
+}
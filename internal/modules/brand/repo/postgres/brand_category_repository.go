@@ -55,6 +55,17 @@ func (r *BrandRepository) ListBrandCategories(ctx context.Context, filter domain
 	return categories, total, rows.Err()
 }
 
+func (r *BrandRepository) GetBrandCategoryByID(ctx context.Context, id int64) (domain.BrandCategory, error) {
+	const query = `
+		SELECT id, name, slug
+		FROM public.brand_categories
+		WHERE id = $1`
+
+	var category domain.BrandCategory
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(&category.ID, &category.Name, &category.Slug)
+	return category, err
+}
+
 func (r *BrandRepository) CreateBrandCategory(ctx context.Context, payload domain.BrandCategoryPayload) (domain.BrandCategory, error) {
 	const query = `
 		INSERT INTO public.brand_categories (name, slug)
@@ -123,6 +123,10 @@ func (r *BrandRepository) DeleteBrand(ctx context.Context, id int64) error {
 	return err
 }
 
+func (r *BrandRepository) GetBrandByID(ctx context.Context, id int64) (domain.Brand, error) {
+	return r.getBrandByID(ctx, id)
+}
+
 func (r *BrandRepository) getBrandByID(ctx context.Context, id int64) (domain.Brand, error) {
 	const query = `
 SELECT
@@ -8,10 +8,12 @@ import (
 
 type BrandRepository interface {
 	ListBrandCategories(ctx context.Context, filter domain.BrandCategoryFilter) ([]domain.BrandCategory, int, error)
+	GetBrandCategoryByID(ctx context.Context, id int64) (domain.BrandCategory, error)
 	CreateBrandCategory(ctx context.Context, payload domain.BrandCategoryPayload) (domain.BrandCategory, error)
 	UpdateBrandCategory(ctx context.Context, id int64, payload domain.BrandCategoryPayload) (domain.BrandCategory, error)
 	DeleteBrandCategory(ctx context.Context, id int64) error
 	ListBrands(ctx context.Context, filter domain.BrandFilter) ([]domain.Brand, int, error)
+	GetBrandByID(ctx context.Context, id int64) (domain.Brand, error)
 	CreateBrand(ctx context.Context, payload domain.BrandPayload) (domain.Brand, error)
 	UpdateBrand(ctx context.Context, id int64, payload domain.BrandPayload) (domain.Brand, error)
 	DeleteBrand(ctx context.Context, id int64) error
@@ -46,6 +48,10 @@ func (s *BrandService) ListBrandCategories(ctx context.Context, filter domain.Br
 	}, nil
 }
 
+func (s *BrandService) GetBrandCategory(ctx context.Context, id int64) (domain.BrandCategory, error) {
+	return s.repo.GetBrandCategoryByID(ctx, id)
+}
+
 func (s *BrandService) CreateBrandCategory(ctx context.Context, payload domain.BrandCategoryPayload) (domain.BrandCategory, error) {
 	return s.repo.CreateBrandCategory(ctx, payload)
 }
@@ -78,6 +84,10 @@ func (s *BrandService) ListBrands(ctx context.Context, filter domain.BrandFilter
 	}, nil
 }
 
+func (s *BrandService) GetBrand(ctx context.Context, id int64) (domain.Brand, error) {
+	return s.repo.GetBrandByID(ctx, id)
+}
+
 func (s *BrandService) CreateBrand(ctx context.Context, payload domain.BrandPayload) (domain.Brand, error) {
 	return s.repo.CreateBrand(ctx, payload)
 }
@@ -38,6 +38,17 @@ ORDER BY id`
 	return programs, rows.Err()
 }
 
+func (r *Repository) GetProgramByID(ctx context.Context, id int16) (domain.Program, error) {
+	const query = `
+SELECT id, code, name
+FROM public.lkp_product_program
+WHERE id = $1`
+
+	var program domain.Program
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(&program.ID, &program.Code, &program.Name)
+	return program, err
+}
+
 func (r *Repository) CreateProgram(ctx context.Context, payload domain.ProgramPayload) (domain.Program, error) {
 	const query = `
 INSERT INTO public.lkp_product_program (id, code, name)
@@ -90,6 +101,17 @@ ORDER BY id`
 	return statuses, rows.Err()
 }
 
+func (r *Repository) GetStatusByID(ctx context.Context, id int16) (domain.CertificationStatus, error) {
+	const query = `
+SELECT id, code, name
+FROM public.lkp_cert_status
+WHERE id = $1`
+
+	var status domain.CertificationStatus
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(&status.ID, &status.Code, &status.Name)
+	return status, err
+}
+
 func (r *Repository) CreateStatus(ctx context.Context, payload domain.StatusPayload) (domain.CertificationStatus, error) {
 	const query = `
 INSERT INTO public.lkp_cert_status (id, code, name)
@@ -8,10 +8,12 @@ import (
 
 type Repository interface {
 	ListPrograms(ctx context.Context) ([]domain.Program, error)
+	GetProgramByID(ctx context.Context, id int16) (domain.Program, error)
 	CreateProgram(ctx context.Context, payload domain.ProgramPayload) (domain.Program, error)
 	UpdateProgram(ctx context.Context, id int16, payload domain.ProgramPayload) (domain.Program, error)
 	DeleteProgram(ctx context.Context, id int16) error
 	ListStatuses(ctx context.Context) ([]domain.CertificationStatus, error)
+	GetStatusByID(ctx context.Context, id int16) (domain.CertificationStatus, error)
 	CreateStatus(ctx context.Context, payload domain.StatusPayload) (domain.CertificationStatus, error)
 	UpdateStatus(ctx context.Context, id int16, payload domain.StatusPayload) (domain.CertificationStatus, error)
 	DeleteStatus(ctx context.Context, id int16) error
@@ -29,6 +31,10 @@ func (s *Service) ListPrograms(ctx context.Context) ([]domain.Program, error) {
 	return s.repo.ListPrograms(ctx)
 }
 
+func (s *Service) GetProgram(ctx context.Context, id int16) (domain.Program, error) {
+	return s.repo.GetProgramByID(ctx, id)
+}
+
 func (s *Service) CreateProgram(ctx context.Context, payload domain.ProgramPayload) (domain.Program, error) {
 	return s.repo.CreateProgram(ctx, payload)
 }
@@ -45,6 +51,10 @@ func (s *Service) ListStatuses(ctx context.Context) ([]domain.CertificationStatu
 	return s.repo.ListStatuses(ctx)
 }
 
+func (s *Service) GetStatus(ctx context.Context, id int16) (domain.CertificationStatus, error) {
+	return s.repo.GetStatusByID(ctx, id)
+}
+
 func (s *Service) CreateStatus(ctx context.Context, payload domain.StatusPayload) (domain.CertificationStatus, error) {
 	return s.repo.CreateStatus(ctx, payload)
 }
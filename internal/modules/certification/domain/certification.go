@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type Certification struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Image     string    `json:"image,omitempty"`
+	Program   Program   `json:"program"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Program struct {
+	ID   int16  `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type CertificationFilter struct {
+	ProgramCode string
+	Search      string
+	Page        int
+	PageSize    int
+}
@@ -0,0 +1,7 @@
+package domain
+
+type CertificationPayload struct {
+	Name      string `json:"name" validate:"required,max=180"`
+	Image     string `json:"image" validate:"omitempty"`
+	ProgramID int16  `json:"program_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/certification/domain"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type CertificationRepository struct {
+	DB *sql.DB
+}
+
+func NewCertificationRepository(db *sql.DB) *CertificationRepository {
+	return &CertificationRepository{DB: db}
+}
+
+const certificationFrom = `
+FROM public.certifications c
+JOIN public.lkp_product_program prog ON prog.id = c.program_id
+`
+
+const baseCertificationSelect = `
+SELECT
+    c.id,
+    c.name,
+    c.image,
+    prog.id,
+    prog.code,
+    prog.name,
+    c.created_at,
+    c.updated_at` + certificationFrom
+
+func (r *CertificationRepository) ListCertifications(ctx context.Context, filter domain.CertificationFilter) ([]domain.Certification, int, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if code := strings.TrimSpace(filter.ProgramCode); code != "" {
+		args = append(args, code)
+		conditions = append(conditions, fmt.Sprintf("prog.code = $%d", len(args)))
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		args = append(args, "%"+search+"%")
+		conditions = append(conditions, fmt.Sprintf("c.name ILIKE $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*)"+certificationFrom+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.PageSize
+	offset := 0
+	if filter.Page > 0 && filter.PageSize > 0 {
+		offset = (filter.Page - 1) * filter.PageSize
+	}
+	if limit > 0 && offset >= total {
+		return []domain.Certification{}, total, nil
+	}
+
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString(baseCertificationSelect)
+	queryBuilder.WriteString(where)
+	queryBuilder.WriteString("ORDER BY prog.id, c.name")
+	if limit > 0 {
+		args = append(args, limit)
+		queryBuilder.WriteString(fmt.Sprintf("\nLIMIT $%d", len(args)))
+		if offset > 0 {
+			args = append(args, offset)
+			queryBuilder.WriteString(fmt.Sprintf(" OFFSET $%d", len(args)))
+		}
+	}
+
+	rows, err := r.DB.QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var certifications []domain.Certification
+	for rows.Next() {
+		certification, scanErr := scanCertification(rows)
+		if scanErr != nil {
+			return nil, 0, scanErr
+		}
+		certifications = append(certifications, certification)
+	}
+	return certifications, total, rows.Err()
+}
+
+func (r *CertificationRepository) GetCertificationByID(ctx context.Context, id int64) (domain.Certification, error) {
+	row := r.DB.QueryRowContext(ctx, baseCertificationSelect+`WHERE c.id = $1`, id)
+	return scanCertification(row)
+}
+
+func (r *CertificationRepository) CreateCertification(ctx context.Context, payload domain.CertificationPayload) (domain.Certification, error) {
+	const query = `
+INSERT INTO public.certifications (name, image, program_id)
+VALUES ($1, $2, $3)
+RETURNING id`
+
+	var certificationID int64
+	if err := r.DB.QueryRowContext(ctx, query, strings.TrimSpace(payload.Name), nullableString(payload.Image), payload.ProgramID).Scan(&certificationID); err != nil {
+		return domain.Certification{}, err
+	}
+	return r.GetCertificationByID(ctx, certificationID)
+}
+
+func (r *CertificationRepository) UpdateCertification(ctx context.Context, id int64, payload domain.CertificationPayload) (domain.Certification, error) {
+	const query = `
+UPDATE public.certifications
+SET name = $1,
+    image = $2,
+    program_id = $3,
+    updated_at = NOW()
+WHERE id = $4
+RETURNING id`
+
+	var certificationID int64
+	if err := r.DB.QueryRowContext(ctx, query, strings.TrimSpace(payload.Name), nullableString(payload.Image), payload.ProgramID, id).Scan(&certificationID); err != nil {
+		return domain.Certification{}, err
+	}
+	return r.GetCertificationByID(ctx, certificationID)
+}
+
+// CountCertificationUsage reports how many product certificates reference the
+// certification.
+func (r *CertificationRepository) CountCertificationUsage(ctx context.Context, id int64) (int, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM public.product_has_certification WHERE certification_id = $1`, id).Scan(&count)
+	return count, err
+}
+
+func (r *CertificationRepository) DeleteCertification(ctx context.Context, id int64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM public.certifications WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanCertification(row rowScanner) (domain.Certification, error) {
+	var (
+		certification domain.Certification
+		image         sql.NullString
+	)
+
+	if err := row.Scan(
+		&certification.ID,
+		&certification.Name,
+		&image,
+		&certification.Program.ID,
+		&certification.Program.Code,
+		&certification.Program.Name,
+		&certification.CreatedAt,
+		&certification.UpdatedAt,
+	); err != nil {
+		return domain.Certification{}, err
+	}
+
+	certification.Image = image.String
+	return certification, nil
+}
+
+func nullableString(value string) any {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return value
+}
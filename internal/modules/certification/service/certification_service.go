@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/certification/domain"
+)
+
+var (
+	ErrCertificationExists = errors.New("certification_exists")
+	ErrCertificationInUse  = errors.New("certification_in_use")
+	ErrProgramNotFound     = errors.New("program_not_found")
+)
+
+type CertificationRepository interface {
+	ListCertifications(ctx context.Context, filter domain.CertificationFilter) ([]domain.Certification, int, error)
+	GetCertificationByID(ctx context.Context, id int64) (domain.Certification, error)
+	CreateCertification(ctx context.Context, payload domain.CertificationPayload) (domain.Certification, error)
+	UpdateCertification(ctx context.Context, id int64, payload domain.CertificationPayload) (domain.Certification, error)
+	CountCertificationUsage(ctx context.Context, id int64) (int, error)
+	DeleteCertification(ctx context.Context, id int64) error
+}
+
+type CertificationService struct {
+	repo CertificationRepository
+}
+
+func NewCertificationService(repo CertificationRepository) *CertificationService {
+	return &CertificationService{repo: repo}
+}
+
+func (s *CertificationService) ListCertifications(ctx context.Context, filter domain.CertificationFilter) (domain.CertificationListResponse, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+
+	items, total, err := s.repo.ListCertifications(ctx, filter)
+	if err != nil {
+		return domain.CertificationListResponse{}, err
+	}
+	return domain.CertificationListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func (s *CertificationService) GetCertification(ctx context.Context, id int64) (domain.Certification, error) {
+	return s.repo.GetCertificationByID(ctx, id)
+}
+
+func (s *CertificationService) CreateCertification(ctx context.Context, payload domain.CertificationPayload) (domain.Certification, error) {
+	certification, err := s.repo.CreateCertification(ctx, payload)
+	return certification, mapWriteError(err)
+}
+
+// UpdateCertification keeps the program fixed once product certificates use the
+// certification, since those products belong to the original program.
+func (s *CertificationService) UpdateCertification(ctx context.Context, id int64, payload domain.CertificationPayload) (domain.Certification, error) {
+	current, err := s.repo.GetCertificationByID(ctx, id)
+	if err != nil {
+		return domain.Certification{}, err
+	}
+	if current.Program.ID != payload.ProgramID {
+		count, err := s.repo.CountCertificationUsage(ctx, id)
+		if err != nil {
+			return domain.Certification{}, err
+		}
+		if count > 0 {
+			return domain.Certification{}, ErrCertificationInUse
+		}
+	}
+
+	certification, err := s.repo.UpdateCertification(ctx, id, payload)
+	return certification, mapWriteError(err)
+}
+
+// DeleteCertification refuses to remove certifications still referenced by
+// product certificates; the FK check covers rows added concurrently.
+func (s *CertificationService) DeleteCertification(ctx context.Context, id int64) error {
+	count, err := s.repo.CountCertificationUsage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCertificationInUse
+	}
+
+	err = s.repo.DeleteCertification(ctx, id)
+	if db.IsForeignKeyViolation(err, "fk_phc_cert") {
+		return ErrCertificationInUse
+	}
+	return err
+}
+
+func mapWriteError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case db.IsUniqueViolation(err, "uk_certifications_name_program"):
+		return ErrCertificationExists
+	case db.IsForeignKeyViolation(err):
+		return ErrProgramNotFound
+	default:
+		return err
+	}
+}
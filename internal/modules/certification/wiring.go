@@ -0,0 +1,21 @@
+package certification
+
+import (
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/certification/repo/postgres"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/certification/service"
+)
+
+type Module struct {
+	Repository *postgres.CertificationRepository
+	Service    *service.CertificationService
+}
+
+func Provide(db *sql.DB) *Module {
+	repo := postgres.NewCertificationRepository(db)
+	return &Module{
+		Repository: repo,
+		Service:    service.NewCertificationService(repo),
+	}
+}
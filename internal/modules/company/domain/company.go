@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type Company struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Email       string    `json:"email,omitempty"`
+	Website     string    `json:"website,omitempty"`
+	PhoneNumber string    `json:"phone_number,omitempty"`
+	Address     string    `json:"address,omitempty"`
+	Image       string    `json:"image,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CompanyFilter struct {
+	Search   string
+	Page     int
+	PageSize int
+}
@@ -0,0 +1,11 @@
+package domain
+
+type CompanyPayload struct {
+	Name        string `json:"name" validate:"required,max=200"`
+	Slug        string `json:"slug" validate:"required,max=200"`
+	Email       string `json:"email" validate:"omitempty,email,max=190"`
+	Website     string `json:"website" validate:"omitempty,url,max=255"`
+	PhoneNumber string `json:"phone_number" validate:"omitempty,max=60"`
+	Address     string `json:"address" validate:"omitempty"`
+	Image       string `json:"image" validate:"omitempty"`
+}
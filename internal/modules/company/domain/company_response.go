@@ -0,0 +1,8 @@
+package domain
+
+type CompanyListResponse struct {
+	Items    []Company `json:"items"`
+	Total    int       `json:"total"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+}
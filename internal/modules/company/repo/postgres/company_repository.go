@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/company/domain"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type CompanyRepository struct {
+	DB *sql.DB
+}
+
+func NewCompanyRepository(db *sql.DB) *CompanyRepository {
+	return &CompanyRepository{DB: db}
+}
+
+const baseCompanySelect = `
+SELECT
+    id,
+    name,
+    slug,
+    email,
+    website,
+    phone_number,
+    address,
+    image,
+    created_at,
+    updated_at
+FROM public.companies
+`
+
+func (r *CompanyRepository) ListCompanies(ctx context.Context, filter domain.CompanyFilter) ([]domain.Company, int, error) {
+	search := strings.TrimSpace(filter.Search)
+
+	const countQuery = `
+SELECT COUNT(*)
+FROM public.companies
+WHERE ($1 = '' OR name ILIKE '%' || $1 || '%' OR slug ILIKE '%' || $1 || '%' OR coalesce(email, '') ILIKE '%' || $1 || '%')`
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, countQuery, search).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.PageSize
+	offset := 0
+	if filter.Page > 0 && filter.PageSize > 0 {
+		offset = (filter.Page - 1) * filter.PageSize
+	}
+	if limit > 0 && offset >= total {
+		return []domain.Company{}, total, nil
+	}
+
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString(baseCompanySelect)
+	queryBuilder.WriteString(`WHERE ($1 = '' OR name ILIKE '%' || $1 || '%' OR slug ILIKE '%' || $1 || '%' OR coalesce(email, '') ILIKE '%' || $1 || '%')
+ORDER BY name`)
+
+	args := []any{search}
+	if limit > 0 {
+		args = append(args, limit)
+		queryBuilder.WriteString(fmt.Sprintf("\nLIMIT $%d", len(args)))
+		if offset > 0 {
+			args = append(args, offset)
+			queryBuilder.WriteString(fmt.Sprintf(" OFFSET $%d", len(args)))
+		}
+	}
+
+	rows, err := r.DB.QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var companies []domain.Company
+	for rows.Next() {
+		company, scanErr := scanCompany(rows)
+		if scanErr != nil {
+			return nil, 0, scanErr
+		}
+		companies = append(companies, company)
+	}
+	return companies, total, rows.Err()
+}
+
+func (r *CompanyRepository) GetCompanyByID(ctx context.Context, id int64) (domain.Company, error) {
+	row := r.DB.QueryRowContext(ctx, baseCompanySelect+`WHERE id = $1`, id)
+	return scanCompany(row)
+}
+
+func (r *CompanyRepository) CreateCompany(ctx context.Context, payload domain.CompanyPayload) (domain.Company, error) {
+	const query = `
+INSERT INTO public.companies (name, slug, email, website, phone_number, address, image)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id`
+
+	var companyID int64
+	if err := r.DB.QueryRowContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Slug,
+		nullableString(payload.Email),
+		nullableString(payload.Website),
+		nullableString(payload.PhoneNumber),
+		nullableString(payload.Address),
+		nullableString(payload.Image),
+	).Scan(&companyID); err != nil {
+		return domain.Company{}, err
+	}
+	return r.GetCompanyByID(ctx, companyID)
+}
+
+func (r *CompanyRepository) UpdateCompany(ctx context.Context, id int64, payload domain.CompanyPayload) (domain.Company, error) {
+	const query = `
+UPDATE public.companies
+SET name = $1,
+    slug = $2,
+    email = $3,
+    website = $4,
+    phone_number = $5,
+    address = $6,
+    image = $7,
+    updated_at = NOW()
+WHERE id = $8
+RETURNING id`
+
+	var companyID int64
+	if err := r.DB.QueryRowContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Slug,
+		nullableString(payload.Email),
+		nullableString(payload.Website),
+		nullableString(payload.PhoneNumber),
+		nullableString(payload.Address),
+		nullableString(payload.Image),
+		id,
+	).Scan(&companyID); err != nil {
+		return domain.Company{}, err
+	}
+	return r.GetCompanyByID(ctx, companyID)
+}
+
+func (r *CompanyRepository) UpdateCompanyImage(ctx context.Context, id int64, image string) (domain.Company, error) {
+	const query = `
+UPDATE public.companies
+SET image = $1,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING id`
+
+	var companyID int64
+	if err := r.DB.QueryRowContext(ctx, query, nullableString(image), id).Scan(&companyID); err != nil {
+		return domain.Company{}, err
+	}
+	return r.GetCompanyByID(ctx, companyID)
+}
+
+func (r *CompanyRepository) DeleteCompany(ctx context.Context, id int64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM public.companies WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanCompany(row rowScanner) (domain.Company, error) {
+	var (
+		company     domain.Company
+		email       sql.NullString
+		website     sql.NullString
+		phoneNumber sql.NullString
+		address     sql.NullString
+		image       sql.NullString
+	)
+
+	if err := row.Scan(
+		&company.ID,
+		&company.Name,
+		&company.Slug,
+		&email,
+		&website,
+		&phoneNumber,
+		&address,
+		&image,
+		&company.CreatedAt,
+		&company.UpdatedAt,
+	); err != nil {
+		return domain.Company{}, err
+	}
+
+	company.Email = email.String
+	company.Website = website.String
+	company.PhoneNumber = phoneNumber.String
+	company.Address = address.String
+	company.Image = image.String
+	return company, nil
+}
+
+func nullableString(value string) any {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/company/domain"
+)
+
+var (
+	ErrCompanySlugExists = errors.New("company_slug_exists")
+	ErrCompanyInUse      = errors.New("company_in_use")
+)
+
+type CompanyRepository interface {
+	ListCompanies(ctx context.Context, filter domain.CompanyFilter) ([]domain.Company, int, error)
+	GetCompanyByID(ctx context.Context, id int64) (domain.Company, error)
+	CreateCompany(ctx context.Context, payload domain.CompanyPayload) (domain.Company, error)
+	UpdateCompany(ctx context.Context, id int64, payload domain.CompanyPayload) (domain.Company, error)
+	UpdateCompanyImage(ctx context.Context, id int64, image string) (domain.Company, error)
+	DeleteCompany(ctx context.Context, id int64) error
+}
+
+type CompanyService struct {
+	repo CompanyRepository
+}
+
+func NewCompanyService(repo CompanyRepository) *CompanyService {
+	return &CompanyService{repo: repo}
+}
+
+func (s *CompanyService) ListCompanies(ctx context.Context, filter domain.CompanyFilter) (domain.CompanyListResponse, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+
+	items, total, err := s.repo.ListCompanies(ctx, filter)
+	if err != nil {
+		return domain.CompanyListResponse{}, err
+	}
+	return domain.CompanyListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func (s *CompanyService) GetCompany(ctx context.Context, id int64) (domain.Company, error) {
+	return s.repo.GetCompanyByID(ctx, id)
+}
+
+func (s *CompanyService) CreateCompany(ctx context.Context, payload domain.CompanyPayload) (domain.Company, error) {
+	company, err := s.repo.CreateCompany(ctx, payload)
+	if db.IsUniqueViolation(err, "uk_companies_slug") {
+		return domain.Company{}, ErrCompanySlugExists
+	}
+	return company, err
+}
+
+func (s *CompanyService) UpdateCompany(ctx context.Context, id int64, payload domain.CompanyPayload) (domain.Company, error) {
+	company, err := s.repo.UpdateCompany(ctx, id, payload)
+	if db.IsUniqueViolation(err, "uk_companies_slug") {
+		return domain.Company{}, ErrCompanySlugExists
+	}
+	return company, err
+}
+
+func (s *CompanyService) UpdateCompanyImage(ctx context.Context, id int64, image string) (domain.Company, error) {
+	return s.repo.UpdateCompanyImage(ctx, id, image)
+}
+
+func (s *CompanyService) DeleteCompany(ctx context.Context, id int64) error {
+	err := s.repo.DeleteCompany(ctx, id)
+	if db.IsForeignKeyViolation(err) {
+		return ErrCompanyInUse
+	}
+	return err
+}
@@ -0,0 +1,21 @@
+package company
+
+import (
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/company/repo/postgres"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/company/service"
+)
+
+type Module struct {
+	Repository *postgres.CompanyRepository
+	Service    *service.CompanyService
+}
+
+func Provide(db *sql.DB) *Module {
+	repo := postgres.NewCompanyRepository(db)
+	return &Module{
+		Repository: repo,
+		Service:    service.NewCompanyService(repo),
+	}
+}
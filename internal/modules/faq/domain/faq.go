@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
+)
 
 type FAQ struct {
 	ID        int64     `json:"id"`
@@ -16,13 +20,16 @@ type FAQPayload struct {
 }
 
 type FAQFilter struct {
-	Page     int `json:"-" validate:"omitempty,min=1"`
-	PageSize int `json:"-" validate:"omitempty,min=1,max=100"`
+	Page      int                `json:"-" validate:"omitempty,min=1"`
+	PageSize  int                `json:"-" validate:"omitempty,min=1,max=100"`
+	Cursor    *pagination.Cursor `json:"-"`
+	SkipTotal bool               `json:"-"`
 }
 
 type FAQListResponse struct {
-	Items    []FAQ `json:"items"`
-	Total    int   `json:"total"`
-	Page     int   `json:"page"`
-	PageSize int   `json:"page_size"`
+	Items      []FAQ  `json:"items"`
+	Total      *int   `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/faq/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
 )
 
 type rowScanner interface {
@@ -30,28 +31,40 @@ WHERE deleted_at IS NULL
 `
 )
 
-func (repository *FAQRepository) ListFAQs(ctx context.Context, filter domain.FAQFilter) ([]domain.FAQ, int, error) {
-	var total int
-	if err := repository.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM public.faqs WHERE deleted_at IS NULL`).Scan(&total); err != nil {
-		return nil, 0, err
+// ListFAQs returns a page of FAQs, newest first. A cursor in the filter
+// replaces the offset, and the total is only counted unless SkipTotal is set.
+func (repository *FAQRepository) ListFAQs(ctx context.Context, filter domain.FAQFilter) ([]domain.FAQ, pagination.Result, error) {
+	var result pagination.Result
+	if !filter.SkipTotal {
+		var total int
+		if err := repository.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM public.faqs WHERE deleted_at IS NULL`).Scan(&total); err != nil {
+			return nil, result, err
+		}
+		result.Total = &total
 	}
 
 	limit := filter.PageSize
 	offset := 0
-	if filter.Page > 0 && filter.PageSize > 0 {
+	if filter.Cursor == nil && filter.Page > 0 && filter.PageSize > 0 {
 		offset = (filter.Page - 1) * filter.PageSize
 	}
-	if limit > 0 && offset >= total {
-		return []domain.FAQ{}, total, nil
+	if limit > 0 && result.Total != nil && offset >= *result.Total {
+		return []domain.FAQ{}, result, nil
 	}
 
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString(baseSelectFAQ)
-	queryBuilder.WriteString("ORDER BY created_at DESC, id DESC")
 
 	var args []any
+	if filter.Cursor != nil {
+		queryBuilder.WriteString("AND ")
+		queryBuilder.WriteString(filter.Cursor.Condition("created_at", "id", &args))
+		queryBuilder.WriteRune('\n')
+	}
+	queryBuilder.WriteString("ORDER BY created_at DESC, id DESC")
+
 	if limit > 0 {
-		args = append(args, limit)
+		args = append(args, limit+1)
 		queryBuilder.WriteString(fmt.Sprintf("\nLIMIT $%d", len(args)))
 		if offset > 0 {
 			args = append(args, offset)
@@ -61,7 +74,7 @@ func (repository *FAQRepository) ListFAQs(ctx context.Context, filter domain.FAQ
 
 	rows, err := repository.DB.QueryContext(ctx, queryBuilder.String(), args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, result, err
 	}
 	defer rows.Close()
 
@@ -69,15 +82,18 @@ func (repository *FAQRepository) ListFAQs(ctx context.Context, filter domain.FAQ
 	for rows.Next() {
 		faq, scanErr := scanFAQ(rows)
 		if scanErr != nil {
-			return nil, 0, scanErr
+			return nil, result, scanErr
 		}
 		faqs = append(faqs, faq)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, result, err
 	}
 
-	return faqs, total, nil
+	faqs, result.Next = pagination.Trim(faqs, limit, func(faq domain.FAQ) pagination.Cursor {
+		return pagination.Cursor{Time: faq.CreatedAt, ID: faq.ID}
+	})
+	return faqs, result, nil
 }
 
 func (repository *FAQRepository) GetFAQByID(ctx context.Context, id int64) (*domain.FAQ, error) {
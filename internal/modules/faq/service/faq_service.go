@@ -4,10 +4,11 @@ import (
 	"context"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/faq/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
 )
 
 type Repository interface {
-	ListFAQs(ctx context.Context, filter domain.FAQFilter) ([]domain.FAQ, int, error)
+	ListFAQs(ctx context.Context, filter domain.FAQFilter) ([]domain.FAQ, pagination.Result, error)
 	CreateFAQ(ctx context.Context, payload domain.FAQPayload) (domain.FAQ, error)
 	GetFAQByID(ctx context.Context, id int64) (*domain.FAQ, error)
 	UpdateFAQ(ctx context.Context, id int64, payload domain.FAQPayload) (domain.FAQ, error)
@@ -23,23 +24,26 @@ func NewService(repo Repository) *Service {
 }
 
 func (s *Service) ListFAQs(ctx context.Context, filter domain.FAQFilter) (domain.FAQListResponse, error) {
-	if filter.Page <= 0 {
+	if filter.Cursor != nil {
+		filter.Page = 0
+	} else if filter.Page <= 0 {
 		filter.Page = 1
 	}
 	if filter.PageSize <= 0 {
 		filter.PageSize = 20
 	}
 
-	items, total, err := s.repo.ListFAQs(ctx, filter)
+	items, page, err := s.repo.ListFAQs(ctx, filter)
 	if err != nil {
 		return domain.FAQListResponse{}, err
 	}
 
 	return domain.FAQListResponse{
-		Items:    items,
-		Total:    total,
-		Page:     filter.Page,
-		PageSize: filter.PageSize,
+		Items:      items,
+		Total:      page.Total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		NextCursor: page.NextCursor(),
 	}, nil
 }
 
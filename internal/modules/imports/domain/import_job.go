@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ModeAllOrNothing = "all_or_nothing"
+	ModePartial      = "partial"
+
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+
+	// RowImported rows were written and committed.
+	RowImported = "imported"
+	// RowValid rows passed every check but were not committed, either because
+	// the job was a dry run or because another row failed an all-or-nothing
+	// import.
+	RowValid  = "valid"
+	RowFailed = "failed"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported_import_format")
+	// ErrInvalidFile wraps errors reading a malformed CSV or XLSX file.
+	ErrInvalidFile    = errors.New("invalid_import_file")
+	ErrMissingColumns = errors.New("missing_import_columns")
+	ErrUnknownColumns = errors.New("unknown_import_columns")
+	ErrNoRows         = errors.New("empty_import_file")
+	ErrTooManyRows    = errors.New("too_many_import_rows")
+	ErrInvalidMode    = errors.New("invalid_import_mode")
+	ErrJobNotFound    = errors.New("import_job_not_found")
+	// ErrAmbiguousReference means a brand or company reference matched more
+	// than one row.
+	ErrAmbiguousReference = errors.New("ambiguous_reference")
+)
+
+// Columns lists the accepted import columns in template order. Headers are
+// matched case-insensitively, with spaces treated as underscores.
+var Columns = []string{
+	"product_name",
+	"product_slug",
+	"program",
+	"brand",
+	"company",
+	"features",
+	"reason",
+	"is_active",
+	"certification",
+	"certificate_no",
+	"issue_date",
+	"expiry_date",
+	"status",
+}
+
+// RequiredColumns must be present in every import file.
+var RequiredColumns = []string{"product_slug", "program", "brand", "company"}
+
+// Row is one data row of an import file keyed by column name. Line is the
+// 1-based line or spreadsheet row number it came from.
+type Row struct {
+	Line   int               `json:"line"`
+	Values map[string]string `json:"values"`
+}
+
+// RowResult reports what happened to one row.
+type RowResult struct {
+	Line           int               `json:"line"`
+	Status         string            `json:"status"`
+	ProductSlug    string            `json:"product_slug,omitempty"`
+	ProductCreated bool              `json:"product_created"`
+	CertificateID  *int64            `json:"certificate_id,omitempty"`
+	Errors         map[string]string `json:"errors,omitempty"`
+}
+
+type Job struct {
+	ID            int64       `json:"id"`
+	Filename      string      `json:"filename"`
+	Format        string      `json:"format"`
+	Mode          string      `json:"mode"`
+	DryRun        bool        `json:"dry_run"`
+	Status        string      `json:"status"`
+	Attempts      int         `json:"attempts"`
+	TotalRows     int         `json:"total_rows"`
+	SucceededRows int         `json:"succeeded_rows"`
+	FailedRows    int         `json:"failed_rows"`
+	Committed     bool        `json:"committed"`
+	Results       []RowResult `json:"results,omitempty"`
+	Error         string      `json:"error,omitempty"`
+	CreatedBy     string      `json:"created_by,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	StartedAt     *time.Time  `json:"started_at,omitempty"`
+	FinishedAt    *time.Time  `json:"finished_at,omitempty"`
+	Rows          []Row       `json:"-"`
+}
+
+// NewJob is the data needed to queue an import.
+type NewJob struct {
+	Filename  string
+	Format    string
+	Mode      string
+	DryRun    bool
+	Rows      []Row
+	CreatedBy string
+}
+
+// JobOutcome is what a finished run writes back to its job.
+type JobOutcome struct {
+	Status        string
+	SucceededRows int
+	FailedRows    int
+	Committed     bool
+	Results       []RowResult
+	Error         string
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// Session writes the rows of one import inside a single transaction. Lookups
+// return sql.ErrNoRows for unknown references.
+type Session interface {
+	Savepoint(ctx context.Context) error
+	RollbackToSavepoint(ctx context.Context) error
+	ReleaseSavepoint(ctx context.Context) error
+	Commit() error
+	Rollback() error
+	// FinishJob writes the outcome like the repository's FinishJob, but
+	// inside the session, so committed rows and the finished job are saved
+	// together.
+	FinishJob(ctx context.Context, id int64, startedAt time.Time, outcome JobOutcome) error
+
+	ResolveProgram(ctx context.Context, ref string) (int16, error)
+	ResolveBrand(ctx context.Context, ref string) (int64, error)
+	ResolveCompany(ctx context.Context, ref string) (int64, error)
+	ResolveCertification(ctx context.Context, ref string, programID int16) (int64, error)
+	ResolveStatus(ctx context.Context, ref string) (string, error)
+	FindProduct(ctx context.Context, brandID int64, slug string) (int64, int16, error)
+	CreateProduct(ctx context.Context, payload productdomain.ProductPayload) (int64, error)
+	// CreateCertificate and TransitionCertificate apply the product module's
+	// lifecycle rules: certificates start in productdomain.InitialStatus and
+	// every status change is recorded in their history.
+	CreateCertificate(ctx context.Context, productID int64, payload productdomain.ProductCertificationPayload, actorXID string) (int64, error)
+	TransitionCertificate(ctx context.Context, certificateID int64, from, to, reason, actorXID string) (bool, error)
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/domain"
+	productpostgres "github.com/Nassabiq/gpci-compro-api/internal/modules/product/repo/postgres"
+)
+
+type ImportJobRepository struct {
+	DB *sql.DB
+}
+
+func NewImportJobRepository(db *sql.DB) *ImportJobRepository {
+	return &ImportJobRepository{DB: db}
+}
+
+func (r *ImportJobRepository) CreateJob(ctx context.Context, job domain.NewJob) (int64, error) {
+	rowsJSON, err := json.Marshal(job.Rows)
+	if err != nil {
+		return 0, err
+	}
+
+	const query = `
+INSERT INTO public.import_jobs (filename, format, mode, dry_run, total_rows, rows_data, created_by)
+VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7)
+RETURNING id`
+
+	var createdBy any
+	if job.CreatedBy != "" {
+		createdBy = job.CreatedBy
+	}
+	var id int64
+	err = r.DB.QueryRowContext(ctx, query, job.Filename, job.Format, job.Mode, job.DryRun, len(job.Rows), rowsJSON, createdBy).Scan(&id)
+	return id, err
+}
+
+// GetJob loads a job, including its source rows when withRows is set.
+func (r *ImportJobRepository) GetJob(ctx context.Context, id int64, withRows bool) (domain.Job, error) {
+	const query = `
+SELECT id, filename, format, mode, dry_run, status, attempts, total_rows, succeeded_rows, failed_rows,
+       committed, results, error, created_by, created_at, started_at, finished_at,
+       CASE WHEN $2 THEN rows_data END
+FROM public.import_jobs
+WHERE id = $1`
+
+	var (
+		job        domain.Job
+		results    []byte
+		rows       []byte
+		errMessage sql.NullString
+		createdBy  sql.NullString
+		startedAt  sql.NullTime
+		finishedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, query, id, withRows).Scan(
+		&job.ID,
+		&job.Filename,
+		&job.Format,
+		&job.Mode,
+		&job.DryRun,
+		&job.Status,
+		&job.Attempts,
+		&job.TotalRows,
+		&job.SucceededRows,
+		&job.FailedRows,
+		&job.Committed,
+		&results,
+		&errMessage,
+		&createdBy,
+		&job.CreatedAt,
+		&startedAt,
+		&finishedAt,
+		&rows,
+	)
+	if err != nil {
+		return domain.Job{}, err
+	}
+
+	if len(results) > 0 {
+		if err := json.Unmarshal(results, &job.Results); err != nil {
+			return domain.Job{}, err
+		}
+	}
+	if len(rows) > 0 {
+		if err := json.Unmarshal(rows, &job.Rows); err != nil {
+			return domain.Job{}, err
+		}
+	}
+	job.Error = errMessage.String
+	job.CreatedBy = createdBy.String
+	if startedAt.Valid {
+		t := startedAt.Time
+		job.StartedAt = &t
+	}
+	if finishedAt.Valid {
+		t := finishedAt.Time
+		job.FinishedAt = &t
+	}
+	return job, nil
+}
+
+// StartJob claims a job for a run: a queued job, or a running one whose run
+// started before staleBefore and is presumed dead, as long as fewer than
+// maxAttempts runs claimed it. It returns the new started_at, which
+// identifies the claim, and false when the job cannot be claimed.
+func (r *ImportJobRepository) StartJob(ctx context.Context, id int64, staleBefore time.Time, maxAttempts int) (time.Time, bool, error) {
+	const query = `
+UPDATE public.import_jobs
+SET status = 'running', started_at = NOW(), attempts = attempts + 1
+WHERE id = $1
+  AND (status = 'queued' OR (status = 'running' AND started_at < $2))
+  AND attempts < $3
+RETURNING started_at`
+
+	var startedAt time.Time
+	err := r.DB.QueryRowContext(ctx, query, id, staleBefore, maxAttempts).Scan(&startedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return startedAt, true, nil
+}
+
+// RequeueJob puts a job back to queued so a retried task can start it again.
+// It does nothing unless the claim started at startedAt still holds the job.
+func (r *ImportJobRepository) RequeueJob(ctx context.Context, id int64, startedAt time.Time) error {
+	const query = `
+UPDATE public.import_jobs
+SET status = 'queued', started_at = NULL
+WHERE id = $1 AND status = 'running' AND started_at = $2`
+
+	_, err := r.DB.ExecContext(ctx, query, id, startedAt)
+	return err
+}
+
+// FinishJob writes the outcome of the run that claimed the job at startedAt.
+// It returns sql.ErrNoRows when that claim no longer holds the job.
+func (r *ImportJobRepository) FinishJob(ctx context.Context, id int64, startedAt time.Time, outcome domain.JobOutcome) error {
+	return finishJob(ctx, r.DB, id, startedAt, outcome)
+}
+
+// FailJob marks an unfinished job failed. Finished jobs are left alone.
+func (r *ImportJobRepository) FailJob(ctx context.Context, id int64, message string) error {
+	const query = `
+UPDATE public.import_jobs
+SET status = 'failed', error = $2, finished_at = NOW()
+WHERE id = $1 AND status IN ('queued', 'running')`
+
+	_, err := r.DB.ExecContext(ctx, query, id, message)
+	return err
+}
+
+func finishJob(ctx context.Context, db productpostgres.DBTX, id int64, startedAt time.Time, outcome domain.JobOutcome) error {
+	var results any
+	if outcome.Results != nil {
+		raw, err := json.Marshal(outcome.Results)
+		if err != nil {
+			return err
+		}
+		results = raw
+	}
+	var errMessage any
+	if outcome.Error != "" {
+		errMessage = outcome.Error
+	}
+
+	const query = `
+UPDATE public.import_jobs
+SET status = $3,
+    succeeded_rows = $4,
+    failed_rows = $5,
+    committed = $6,
+    results = $7::jsonb,
+    error = $8,
+    finished_at = NOW()
+WHERE id = $1 AND status = 'running' AND started_at = $2`
+
+	result, err := db.ExecContext(ctx, query, id, startedAt, outcome.Status, outcome.SucceededRows, outcome.FailedRows, outcome.Committed, results, errMessage)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/domain"
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	productpostgres "github.com/Nassabiq/gpci-compro-api/internal/modules/product/repo/postgres"
+)
+
+// ImportSession writes the rows of one import job inside a single
+// transaction. Each row runs between Savepoint and ReleaseSavepoint or
+// RollbackToSavepoint, so a failed row leaves the others intact.
+type ImportSession struct {
+	tx *sql.Tx
+}
+
+func (r *ImportJobRepository) Begin(ctx context.Context) (domain.Session, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportSession{tx: tx}, nil
+}
+
+func (s *ImportSession) Commit() error {
+	return s.tx.Commit()
+}
+
+func (s *ImportSession) Rollback() error {
+	return s.tx.Rollback()
+}
+
+func (s *ImportSession) FinishJob(ctx context.Context, id int64, startedAt time.Time, outcome domain.JobOutcome) error {
+	return finishJob(ctx, s.tx, id, startedAt, outcome)
+}
+
+func (s *ImportSession) Savepoint(ctx context.Context) error {
+	_, err := s.tx.ExecContext(ctx, "SAVEPOINT import_row")
+	return err
+}
+
+func (s *ImportSession) RollbackToSavepoint(ctx context.Context) error {
+	_, err := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row")
+	return err
+}
+
+func (s *ImportSession) ReleaseSavepoint(ctx context.Context) error {
+	_, err := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row")
+	return err
+}
+
+// ResolveProgram finds a program by code or name.
+func (s *ImportSession) ResolveProgram(ctx context.Context, ref string) (int16, error) {
+	const query = `
+SELECT id FROM public.lkp_product_program
+WHERE code = $1 OR LOWER(name) = LOWER($1)
+ORDER BY (code = $1) DESC
+LIMIT 1`
+
+	var id int16
+	err := s.tx.QueryRowContext(ctx, query, ref).Scan(&id)
+	return id, err
+}
+
+// ResolveBrand finds a brand by slug, then by name.
+func (s *ImportSession) ResolveBrand(ctx context.Context, ref string) (int64, error) {
+	const query = `
+SELECT id, slug = $1
+FROM public.brands
+WHERE slug = $1 OR LOWER(name) = LOWER($1)`
+
+	return s.resolveBySlugOrName(ctx, query, ref)
+}
+
+// ResolveCompany finds a company by slug, then by name.
+func (s *ImportSession) ResolveCompany(ctx context.Context, ref string) (int64, error) {
+	const query = `
+SELECT id, slug = $1
+FROM public.companies
+WHERE slug = $1 OR LOWER(name) = LOWER($1)`
+
+	return s.resolveBySlugOrName(ctx, query, ref)
+}
+
+// ResolveCertification finds a certification by name within a program.
+func (s *ImportSession) ResolveCertification(ctx context.Context, ref string, programID int16) (int64, error) {
+	const query = `
+SELECT id FROM public.certifications
+WHERE LOWER(name) = LOWER($1) AND program_id = $2`
+
+	var id int64
+	err := s.tx.QueryRowContext(ctx, query, ref, programID).Scan(&id)
+	return id, err
+}
+
+// ResolveStatus finds a certificate status by code or name and returns its
+// code.
+func (s *ImportSession) ResolveStatus(ctx context.Context, ref string) (string, error) {
+	const query = `
+SELECT code FROM public.lkp_cert_status
+WHERE code = LOWER($1) OR LOWER(name) = LOWER($1)
+ORDER BY (code = LOWER($1)) DESC
+LIMIT 1`
+
+	var code string
+	err := s.tx.QueryRowContext(ctx, query, ref).Scan(&code)
+	return code, err
+}
+
+// FindProduct returns the ID and program of the brand's product with the
+// given slug.
+func (s *ImportSession) FindProduct(ctx context.Context, brandID int64, slug string) (int64, int16, error) {
+	const query = `SELECT id, program_id FROM public.products WHERE brand_id = $1 AND slug = $2`
+
+	var (
+		id        int64
+		programID int16
+	)
+	err := s.tx.QueryRowContext(ctx, query, brandID, slug).Scan(&id, &programID)
+	return id, programID, err
+}
+
+func (s *ImportSession) CreateProduct(ctx context.Context, payload productdomain.ProductPayload) (int64, error) {
+	return productpostgres.InsertProduct(ctx, s.tx, payload)
+}
+
+func (s *ImportSession) CreateCertificate(ctx context.Context, productID int64, payload productdomain.ProductCertificationPayload, actorXID string) (int64, error) {
+	return productpostgres.InsertCertificate(ctx, s.tx, productID, payload, actorXID)
+}
+
+func (s *ImportSession) TransitionCertificate(ctx context.Context, certificateID int64, from, to, reason, actorXID string) (bool, error) {
+	return productpostgres.TransitionStatus(ctx, s.tx, certificateID, from, to, reason, actorXID)
+}
+
+// resolveBySlugOrName prefers a unique slug match over a unique name match
+// and reports domain.ErrAmbiguousReference when either matches several rows.
+func (s *ImportSession) resolveBySlugOrName(ctx context.Context, query, ref string) (int64, error) {
+	rows, err := s.tx.QueryContext(ctx, query, ref)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var bySlug, byName []int64
+	for rows.Next() {
+		var (
+			id     int64
+			isSlug bool
+		)
+		if err := rows.Scan(&id, &isSlug); err != nil {
+			return 0, err
+		}
+		if isSlug {
+			bySlug = append(bySlug, id)
+		} else {
+			byName = append(byName, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	matches := bySlug
+	if len(matches) == 0 {
+		matches = byName
+	}
+	switch len(matches) {
+	case 0:
+		return 0, sql.ErrNoRows
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, domain.ErrAmbiguousReference
+	}
+}
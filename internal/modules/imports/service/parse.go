@@ -0,0 +1,223 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxRows caps the data rows accepted in one import file.
+const MaxRows = 5000
+
+// dateColumns hold dates; spreadsheet serial numbers in them are converted
+// to YYYY-MM-DD.
+var dateColumns = []string{"issue_date", "expiry_date"}
+
+// ParseFile reads a CSV or XLSX import file into rows keyed by column name.
+// The format is chosen by the file extension, the first line or sheet row must
+// be the header, and blank rows are skipped.
+func ParseFile(filename string, r io.Reader) (string, []domain.Row, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		rows, err := parseCSV(r)
+		return domain.FormatCSV, rows, err
+	case ".xlsx":
+		rows, err := parseXLSX(r)
+		return domain.FormatXLSX, rows, err
+	default:
+		return "", nil, domain.ErrUnsupportedFormat
+	}
+}
+
+func parseCSV(r io.Reader) ([]domain.Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.ErrNoRows
+	}
+	if err != nil {
+		return nil, invalidFile(err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	columns, err := mapHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []domain.Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, invalidFile(err)
+		}
+		line, _ := reader.FieldPos(0)
+		if row, ok := buildRow(line, columns, record); ok {
+			if len(rows) == MaxRows {
+				return nil, domain.ErrTooManyRows
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrNoRows
+	}
+	return rows, nil
+}
+
+// parseXLSX reads the first sheet of a workbook. Cells are read raw so dates
+// arrive as serial numbers regardless of the cell format.
+func parseXLSX(r io.Reader) ([]domain.Row, error) {
+	book, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, invalidFile(err)
+	}
+	defer book.Close()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, domain.ErrNoRows
+	}
+	iter, err := book.Rows(sheets[0])
+	if err != nil {
+		return nil, invalidFile(err)
+	}
+	defer iter.Close()
+
+	var (
+		columns []string
+		rows    []domain.Row
+		line    int
+	)
+	for iter.Next() {
+		line++
+		record, err := iter.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, invalidFile(err)
+		}
+		if columns == nil {
+			if isBlank(record) {
+				continue
+			}
+			if columns, err = mapHeader(record); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		row, ok := buildRow(line, columns, record)
+		if !ok {
+			continue
+		}
+		for _, column := range dateColumns {
+			if value, ok := row.Values[column]; ok {
+				row.Values[column] = excelDate(value)
+			}
+		}
+		if len(rows) == MaxRows {
+			return nil, domain.ErrTooManyRows
+		}
+		rows = append(rows, row)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, invalidFile(err)
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrNoRows
+	}
+	return rows, nil
+}
+
+// invalidFile marks a read error as a problem with the uploaded file rather
+// than with the server. Errors of the underlying reader, such as
+// *csv.ParseError, stay reachable with errors.As.
+func invalidFile(err error) error {
+	return fmt.Errorf("%w: %w", domain.ErrInvalidFile, err)
+}
+
+// mapHeader normalises header cells into column names and rejects unknown or
+// missing columns. Empty header cells are ignored along with their values.
+func mapHeader(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	var unknown []string
+	for i, cell := range header {
+		name := strings.ToLower(strings.Join(strings.Fields(cell), "_"))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(domain.Columns, name) {
+			unknown = append(unknown, cell)
+			continue
+		}
+		columns[i] = name
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownColumns, strings.Join(unknown, ", "))
+	}
+
+	var missing []string
+	for _, required := range domain.RequiredColumns {
+		if !slices.Contains(columns, required) {
+			missing = append(missing, required)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMissingColumns, strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+// buildRow keeps the non-empty cells of a record. It reports false for blank
+// records.
+func buildRow(line int, columns, record []string) (domain.Row, bool) {
+	values := make(map[string]string, len(columns))
+	for i, cell := range record {
+		if i >= len(columns) || columns[i] == "" {
+			continue
+		}
+		if value := strings.TrimSpace(cell); value != "" {
+			values[columns[i]] = value
+		}
+	}
+	if len(values) == 0 {
+		return domain.Row{}, false
+	}
+	return domain.Row{Line: line, Values: values}, true
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// excelDate converts a spreadsheet date serial to YYYY-MM-DD and returns any
+// other value unchanged.
+func excelDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	t, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return value
+	}
+	return t.Format("2006-01-02")
+}
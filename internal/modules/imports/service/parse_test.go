@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestMapHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "normalised names",
+			header: []string{"Product Slug", " PROGRAM ", "brand", "Company", "Issue  Date"},
+			want:   []string{"product_slug", "program", "brand", "company", "issue_date"},
+		},
+		{
+			name:   "empty cells ignored",
+			header: []string{"product_slug", "", "program", "brand", "company"},
+			want:   []string{"product_slug", "", "program", "brand", "company"},
+		},
+		{
+			name:    "unknown column",
+			header:  []string{"product_slug", "program", "brand", "company", "colour"},
+			wantErr: domain.ErrUnknownColumns,
+		},
+		{
+			name:    "missing column",
+			header:  []string{"product_slug", "program", "brand"},
+			wantErr: domain.ErrMissingColumns,
+		},
+	}
+	for _, tt := range tests {
+		got, err := mapHeader(tt.header)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%s: mapHeader = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestExcelDate(t *testing.T) {
+	tests := []struct{ value, want string }{
+		{"45658", "2025-01-01"},
+		{"45658.75", "2025-01-01"},
+		{"2025-01-01", "2025-01-01"},
+		{"01/02/2025", "01/02/2025"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := excelDate(tt.value); got != tt.want {
+			t.Errorf("excelDate(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   bool
+		wantOK bool
+	}{
+		{"yes", true, true},
+		{"Y", true, true},
+		{"ya", true, true},
+		{"true", true, true},
+		{"1", true, true},
+		{"no", false, true},
+		{"Tidak", false, true},
+		{"false", false, true},
+		{"0", false, true},
+		{"maybe", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseBool(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseBool(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseFileCSV(t *testing.T) {
+	data := "\ufeffproduct_slug,program,brand,company,issue_date\n" +
+		"eco-paint,green_label,Acme,Acme Ltd,2025-01-01\n" +
+		",,,,\n" +
+		"  eco-tile , green_label,Acme,Acme Ltd,\n"
+
+	format, rows, err := ParseFile("products.CSV", strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if format != domain.FormatCSV {
+		t.Errorf("format = %q, want %q", format, domain.FormatCSV)
+	}
+	want := []domain.Row{
+		{Line: 2, Values: map[string]string{"product_slug": "eco-paint", "program": "green_label", "brand": "Acme", "company": "Acme Ltd", "issue_date": "2025-01-01"}},
+		{Line: 4, Values: map[string]string{"product_slug": "eco-tile", "program": "green_label", "brand": "Acme", "company": "Acme Ltd"}},
+	}
+	if !rowsEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestParseFileXLSX(t *testing.T) {
+	book := excelize.NewFile()
+	sheet := book.GetSheetName(0)
+	records := [][]any{
+		{"Product Slug", "Program", "Brand", "Company", "Expiry Date"},
+		{"eco-paint", "green_label", "Acme", "Acme Ltd", 45658},
+	}
+	for i, record := range records {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := book.SetSheetRow(sheet, cell, &record); err != nil {
+			t.Fatalf("SetSheetRow: %v", err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := book.Write(&buf); err != nil {
+		t.Fatalf("write workbook: %v", err)
+	}
+
+	format, rows, err := ParseFile("products.xlsx", &buf)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if format != domain.FormatXLSX {
+		t.Errorf("format = %q, want %q", format, domain.FormatXLSX)
+	}
+	want := []domain.Row{
+		{Line: 2, Values: map[string]string{"product_slug": "eco-paint", "program": "green_label", "brand": "Acme", "company": "Acme Ltd", "expiry_date": "2025-01-01"}},
+	}
+	if !rowsEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     string
+		wantErr  error
+	}{
+		{"unsupported format", "products.txt", "product_slug\n", domain.ErrUnsupportedFormat},
+		{"empty file", "products.csv", "", domain.ErrNoRows},
+		{"header only", "products.csv", "product_slug,program,brand,company\n", domain.ErrNoRows},
+		{"unknown column", "products.csv", "product_slug,program,brand,company,colour\na,b,c,d,e\n", domain.ErrUnknownColumns},
+		{"malformed csv", "products.csv", "product_slug,program,brand,company\n\"a,b,c,d\n", domain.ErrInvalidFile},
+		{"not a workbook", "products.xlsx", "not a zip", domain.ErrInvalidFile},
+	}
+	for _, tt := range tests {
+		if _, _, err := ParseFile(tt.filename, strings.NewReader(tt.data)); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func rowsEqual(a, b []domain.Row) bool {
+	return slices.EqualFunc(a, b, func(x, y domain.Row) bool {
+		return x.Line == y.Line && maps.Equal(x.Values, y.Values)
+	})
+}
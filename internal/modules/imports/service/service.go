@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/domain"
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/validator"
+)
+
+type Repository interface {
+	CreateJob(ctx context.Context, job domain.NewJob) (int64, error)
+	GetJob(ctx context.Context, id int64, withRows bool) (domain.Job, error)
+	StartJob(ctx context.Context, id int64, staleBefore time.Time, maxAttempts int) (time.Time, bool, error)
+	RequeueJob(ctx context.Context, id int64, startedAt time.Time) error
+	FinishJob(ctx context.Context, id int64, startedAt time.Time, outcome domain.JobOutcome) error
+	FailJob(ctx context.Context, id int64, message string) error
+	Begin(ctx context.Context) (domain.Session, error)
+}
+
+const (
+	// jobLease is how long a run holds its job before another delivery of
+	// the task may take it over. It outlasts the 30 minute import task
+	// timeout, so a live run is never taken over.
+	jobLease = 45 * time.Minute
+	// maxJobAttempts bounds the runs that may claim a job, so a file that
+	// keeps killing the worker is failed rather than retried forever.
+	maxJobAttempts = 5
+)
+
+// Dispatcher queues import runs and the PDFs of imported valid certificates;
+// queue.Dispatcher implements it.
+type Dispatcher interface {
+	ProcessImport(ctx context.Context, jobID int64) error
+	ProcessImportAt(ctx context.Context, jobID int64, at time.Time) error
+	GenerateCertificateDocument(ctx context.Context, certificateID int64) error
+}
+
+type Service struct {
+	repo       Repository
+	dispatcher Dispatcher
+}
+
+func New(repo Repository, dispatcher Dispatcher) *Service {
+	return &Service{repo: repo, dispatcher: dispatcher}
+}
+
+// payloadColumns maps payload struct fields to the import columns they are
+// filled from, so validation errors name the column to fix.
+var payloadColumns = map[string]string{
+	"CompanyID":       "company",
+	"BrandID":         "brand",
+	"ProgramID":       "program",
+	"Name":            "product_name",
+	"Slug":            "product_slug",
+	"Features":        "features",
+	"Reason":          "reason",
+	"IsActive":        "is_active",
+	"CertificationID": "certification",
+	"CertificateNo":   "certificate_no",
+	"IssueDate":       "issue_date",
+	"ExpiryDate":      "expiry_date",
+}
+
+// certificateColumns only make sense together with a certification.
+var certificateColumns = []string{"certificate_no", "issue_date", "expiry_date", "status"}
+
+// Create parses an import file and queues it for the worker.
+func (s *Service) Create(ctx context.Context, filename string, file io.Reader, mode string, dryRun bool, actorXID string) (domain.Job, error) {
+	if mode == "" {
+		mode = domain.ModeAllOrNothing
+	}
+	if mode != domain.ModeAllOrNothing && mode != domain.ModePartial {
+		return domain.Job{}, domain.ErrInvalidMode
+	}
+
+	format, rows, err := ParseFile(filename, file)
+	if err != nil {
+		return domain.Job{}, err
+	}
+
+	id, err := s.repo.CreateJob(ctx, domain.NewJob{
+		Filename:  filename,
+		Format:    format,
+		Mode:      mode,
+		DryRun:    dryRun,
+		Rows:      rows,
+		CreatedBy: actorXID,
+	})
+	if err != nil {
+		return domain.Job{}, err
+	}
+
+	if err := s.dispatcher.ProcessImport(ctx, id); err != nil {
+		_ = s.repo.FailJob(context.WithoutCancel(ctx), id, "could not queue the import")
+		return domain.Job{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (domain.Job, error) {
+	job, err := s.repo.GetJob(ctx, id, false)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Job{}, domain.ErrJobNotFound
+	}
+	return job, err
+}
+
+// Process runs a queued import. A run claims the job for jobLease. The
+// outcome of a committing run is written in the same transaction as its rows,
+// so a job that is still running has nothing committed and can safely run
+// again: on error the job is put back in the queue for a retry, and a run
+// that died is taken over once its lease expires.
+func (s *Service) Process(ctx context.Context, id int64) error {
+	startedAt, started, err := s.repo.StartJob(ctx, id, time.Now().Add(-jobLease), maxJobAttempts)
+	if err != nil {
+		return err
+	}
+	if !started {
+		return s.skip(ctx, id)
+	}
+
+	job, err := s.repo.GetJob(ctx, id, true)
+	if err != nil {
+		_ = s.repo.RequeueJob(context.WithoutCancel(ctx), id, startedAt)
+		return err
+	}
+
+	documents, err := s.run(ctx, job, startedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another run took the job over; it reports the outcome.
+		return nil
+	}
+	if err != nil {
+		_ = s.repo.RequeueJob(context.WithoutCancel(ctx), id, startedAt)
+		return err
+	}
+
+	// Best effort: a missing PDF can be generated again on demand.
+	for _, certificateID := range documents {
+		_ = s.dispatcher.GenerateCertificateDocument(ctx, certificateID)
+	}
+	return nil
+}
+
+// skip handles a delivery that could not claim its job. Finished jobs need
+// nothing. A job held by another run is checked again when that run's lease
+// expires, in case it died, and a job whose lease expired but that is out of
+// attempts is failed.
+func (s *Service) skip(ctx context.Context, id int64) error {
+	job, err := s.repo.GetJob(ctx, id, false)
+	if err != nil {
+		return err
+	}
+	if job.Status != domain.JobRunning || job.StartedAt == nil {
+		return nil
+	}
+	expires := job.StartedAt.Add(jobLease)
+	if time.Now().Before(expires) {
+		return s.dispatcher.ProcessImportAt(ctx, id, expires.Add(time.Minute))
+	}
+	return s.repo.FailJob(ctx, id, fmt.Sprintf("the import was interrupted %d times", job.Attempts))
+}
+
+// Fail marks a job failed once the worker gives up retrying it.
+func (s *Service) Fail(ctx context.Context, id int64, cause error) error {
+	return s.repo.FailJob(ctx, id, cause.Error())
+}
+
+// run applies every row inside one transaction, each behind a savepoint, and
+// finishes the job claimed at startedAt. It commits the rows together with the
+// finished job unless the job is a dry run or an all-or-nothing import had a
+// failing row, and returns the IDs of committed valid certificates. It
+// returns sql.ErrNoRows when the claim was lost.
+func (s *Service) run(ctx context.Context, job domain.Job, startedAt time.Time) ([]int64, error) {
+	session, err := s.repo.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	closed := false
+	defer func() {
+		if !closed {
+			_ = session.Rollback()
+		}
+	}()
+
+	var (
+		results   = make([]domain.RowResult, 0, len(job.Rows))
+		documents []int64
+		failed    int
+	)
+	for _, row := range job.Rows {
+		if err := session.Savepoint(ctx); err != nil {
+			return nil, err
+		}
+		result, document := s.applyRow(ctx, session, job, row)
+		if result.Status == domain.RowFailed {
+			failed++
+			if err := session.RollbackToSavepoint(ctx); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := session.ReleaseSavepoint(ctx); err != nil {
+				return nil, err
+			}
+			if document && result.CertificateID != nil {
+				documents = append(documents, *result.CertificateID)
+			}
+		}
+		results = append(results, result)
+	}
+
+	commit := !job.DryRun && (job.Mode == domain.ModePartial || failed == 0)
+	if !commit {
+		documents = nil
+	}
+	for i := range results {
+		if results[i].Status == domain.RowFailed {
+			continue
+		}
+		if commit {
+			results[i].Status = domain.RowImported
+		} else {
+			results[i].Status = domain.RowValid
+			results[i].CertificateID = nil
+		}
+	}
+	outcome := domain.JobOutcome{
+		Status:        domain.JobCompleted,
+		SucceededRows: len(results) - failed,
+		FailedRows:    failed,
+		Committed:     commit,
+		Results:       results,
+	}
+
+	if !commit {
+		// Nothing is kept, so finishing separately is safe to retry.
+		if err := session.Rollback(); err != nil {
+			return nil, err
+		}
+		closed = true
+		return nil, s.repo.FinishJob(ctx, job.ID, startedAt, outcome)
+	}
+	if err := session.FinishJob(ctx, job.ID, startedAt, outcome); err != nil {
+		return nil, err
+	}
+	if err := session.Commit(); err != nil {
+		return nil, err
+	}
+	closed = true
+	return documents, nil
+}
+
+// applyRow resolves the references of one row, validates it with the product
+// payload rules and writes it. Certificates are created like through the API
+// and then moved to the row's status with the lifecycle transitions, so the
+// status history shows the import. It reports whether the row created a
+// valid certificate that should get a PDF.
+func (s *Service) applyRow(ctx context.Context, session domain.Session, job domain.Job, row domain.Row) (domain.RowResult, bool) {
+	values := row.Values
+	result := domain.RowResult{Line: row.Line, Status: domain.RowImported, ProductSlug: values["product_slug"]}
+	errs := map[string]string{}
+	fail := func() (domain.RowResult, bool) {
+		result.Status = domain.RowFailed
+		result.Errors = errs
+		return result, false
+	}
+
+	programID, err := session.ResolveProgram(ctx, values["program"])
+	if err != nil {
+		errs["program"] = referenceError(err, "program")
+	}
+	brandID, err := session.ResolveBrand(ctx, values["brand"])
+	if err != nil {
+		errs["brand"] = referenceError(err, "brand")
+	}
+	companyID, err := session.ResolveCompany(ctx, values["company"])
+	if err != nil {
+		errs["company"] = referenceError(err, "company")
+	}
+	if len(errs) > 0 {
+		return fail()
+	}
+
+	productID, productProgramID, err := session.FindProduct(ctx, brandID, values["product_slug"])
+	switch {
+	case err == nil:
+		if productProgramID != programID {
+			errs["program"] = "existing product belongs to another program"
+			return fail()
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		payload := productdomain.ProductPayload{
+			CompanyID: companyID,
+			BrandID:   brandID,
+			ProgramID: programID,
+			Name:      values["product_name"],
+			Slug:      values["product_slug"],
+			Features:  optional(values["features"]),
+			Reason:    optional(values["reason"]),
+		}
+		if raw, ok := values["is_active"]; ok {
+			active, ok := parseBool(raw)
+			if !ok {
+				errs["is_active"] = "must be true or false"
+				return fail()
+			}
+			payload.IsActive = &active
+		}
+		if err := validator.Struct(payload); err != nil {
+			addValidationErrors(errs, err)
+			return fail()
+		}
+		if productID, err = session.CreateProduct(ctx, payload); err != nil {
+			errs["product_slug"] = err.Error()
+			return fail()
+		}
+		result.ProductCreated = true
+	default:
+		errs["product_slug"] = err.Error()
+		return fail()
+	}
+
+	certification, ok := values["certification"]
+	if !ok {
+		for _, column := range certificateColumns {
+			if _, set := values[column]; set {
+				errs["certification"] = "required when certificate columns are set"
+				return fail()
+			}
+		}
+		return result, false
+	}
+
+	certificationID, err := session.ResolveCertification(ctx, certification, programID)
+	if err != nil {
+		errs["certification"] = referenceError(err, "certification in this program")
+	}
+	statusRef := values["status"]
+	if statusRef == "" {
+		statusRef = productdomain.StatusPending
+	}
+	statusCode, err := session.ResolveStatus(ctx, statusRef)
+	if err != nil {
+		errs["status"] = referenceError(err, "status")
+	}
+	transitions, ok := productdomain.TransitionPath(productdomain.InitialStatus, statusCode)
+	if err == nil && !ok {
+		errs["status"] = fmt.Sprintf("%s certificates cannot be imported", statusCode)
+	}
+	issueDate, ok := parseDate(values["issue_date"])
+	if !ok {
+		errs["issue_date"] = "must be a YYYY-MM-DD date"
+	}
+	expiryDate, ok := parseDate(values["expiry_date"])
+	if !ok {
+		errs["expiry_date"] = "must be a YYYY-MM-DD date"
+	}
+	if len(errs) > 0 {
+		return fail()
+	}
+	if issueDate != nil && expiryDate != nil && issueDate.After(*expiryDate) {
+		errs["expiry_date"] = "must be after issue_date"
+		return fail()
+	}
+
+	payload := productdomain.ProductCertificationPayload{
+		CertificationID: certificationID,
+		CertificateNo:   optional(values["certificate_no"]),
+		IssueDate:       issueDate,
+		ExpiryDate:      expiryDate,
+	}
+	if err := validator.Struct(payload); err != nil {
+		addValidationErrors(errs, err)
+		return fail()
+	}
+	certificateID, err := session.CreateCertificate(ctx, productID, payload, job.CreatedBy)
+	if err != nil {
+		if db.IsUniqueViolation(err, "uk_phc_product_cert") {
+			errs["certification"] = "product already has this certification"
+		} else {
+			errs["certification"] = err.Error()
+		}
+		return fail()
+	}
+	from := productdomain.InitialStatus
+	reason := fmt.Sprintf("imported by import job %d", job.ID)
+	for _, action := range transitions {
+		to := productdomain.StatusTransitions[action].To
+		applied, err := session.TransitionCertificate(ctx, certificateID, from, to, reason, job.CreatedBy)
+		if err == nil && !applied {
+			err = fmt.Errorf("could not %s the certificate", action)
+		}
+		if err != nil {
+			errs["status"] = err.Error()
+			return fail()
+		}
+		from = to
+	}
+	result.CertificateID = &certificateID
+	return result, productdomain.CanIssueDocument(statusCode)
+}
+
+func referenceError(err error, what string) string {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return fmt.Sprintf("unknown %s", what)
+	case errors.Is(err, domain.ErrAmbiguousReference):
+		return fmt.Sprintf("matches more than one %s; use the slug", what)
+	default:
+		return err.Error()
+	}
+}
+
+func addValidationErrors(errs map[string]string, err error) {
+	for field, message := range validator.ToMap(err) {
+		if column, ok := payloadColumns[field]; ok {
+			field = column
+		}
+		errs[field] = message
+	}
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "ya":
+		return true, true
+	case "no", "n", "tidak":
+		return false, true
+	}
+	parsed, err := strconv.ParseBool(value)
+	return parsed, err == nil
+}
+
+// parseDate accepts an empty value or a YYYY-MM-DD date.
+func parseDate(value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
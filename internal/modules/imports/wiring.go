@@ -0,0 +1,21 @@
+package imports
+
+import (
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/repo/postgres"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/imports/service"
+)
+
+type Module struct {
+	Repository *postgres.ImportJobRepository
+	Service    *service.Service
+}
+
+func Provide(db *sql.DB, dispatcher service.Dispatcher) *Module {
+	repo := postgres.NewImportJobRepository(db)
+	return &Module{
+		Repository: repo,
+		Service:    service.New(repo, dispatcher),
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+// CanIssueDocument reports whether an official certificate PDF can be
+// rendered for a certificate in the given status. Only valid certificates
+// carry a document; other statuses keep whatever file they already have.
+func CanIssueDocument(status string) bool {
+	return status == StatusValid
+}
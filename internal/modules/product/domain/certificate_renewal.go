@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// RenewableStatuses are the statuses a certificate can be renewed from.
+var RenewableStatuses = []string{StatusValid, StatusExpired}
+
+// CanRenew reports whether a certificate in the given status can be renewed.
+func CanRenew(status string) bool {
+	return slices.Contains(RenewableStatuses, status)
+}
+
+type CertificateRenewalPayload struct {
+	CertificateNo string     `json:"certificate_no" validate:"required,max=140"`
+	IssueDate     *time.Time `json:"issue_date" validate:"required"`
+	ExpiryDate    *time.Time `json:"expiry_date" validate:"required"`
+	DocumentFile  *string    `json:"document_file" validate:"omitempty"`
+	Reason        string     `json:"reason" validate:"omitempty,max=1000"`
+}
+
+// CertificateRenewal is the archived set of terms a renewal replaced.
+type CertificateRenewal struct {
+	ID            int64      `json:"id"`
+	CertificateNo string     `json:"certificate_no,omitempty"`
+	IssueDate     *time.Time `json:"issue_date,omitempty"`
+	ExpiryDate    *time.Time `json:"expiry_date,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	DocumentFile  string     `json:"document_file,omitempty"`
+	RenewedBy     string     `json:"renewed_by,omitempty"`
+	RenewedAt     time.Time  `json:"renewed_at"`
+}
+
+// ProgramCertificateDetail is a certificate together with the terms it
+// replaced, most recent renewal first.
+type ProgramCertificateDetail struct {
+	ProgramCertificate
+	Renewals []CertificateRenewal `json:"renewals"`
+}
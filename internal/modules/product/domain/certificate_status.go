@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Certificate status codes from lkp_cert_status.
+const (
+	StatusPending   = "pending"
+	StatusValid     = "valid"
+	StatusSuspended = "suspended"
+	StatusExpired   = "expired"
+	StatusRevoked   = "revoked"
+)
+
+// Status transition actions exposed by the certificate endpoints.
+const (
+	TransitionApprove   = "approve"
+	TransitionSuspend   = "suspend"
+	TransitionReinstate = "reinstate"
+	TransitionRevoke    = "revoke"
+)
+
+// InitialStatus is the status every certificate is created with. It only
+// changes through StatusTransitions, renewal or the expiry job.
+const InitialStatus = StatusPending
+
+// StatusTransition moves a certificate from any of From to To.
+type StatusTransition struct {
+	From []string
+	To   string
+}
+
+// StatusTransitions is the certificate lifecycle. Revoked is terminal, and
+// expired certificates only leave that state through renewal. Expiry itself
+// is applied by the scheduled expiry job rather than an endpoint.
+var StatusTransitions = map[string]StatusTransition{
+	TransitionApprove:   {From: []string{StatusPending}, To: StatusValid},
+	TransitionSuspend:   {From: []string{StatusValid}, To: StatusSuspended},
+	TransitionReinstate: {From: []string{StatusSuspended}, To: StatusValid},
+	TransitionRevoke:    {From: []string{StatusPending, StatusValid, StatusSuspended, StatusExpired}, To: StatusRevoked},
+}
+
+// Allows reports whether the transition may start from the given status.
+func (t StatusTransition) Allows(from string) bool {
+	return slices.Contains(t.From, from)
+}
+
+// TransitionPath returns the shortest list of transition actions that takes
+// a certificate from one status to another. It reports false when to cannot
+// be reached through transitions, e.g. expired.
+func TransitionPath(from, to string) ([]string, bool) {
+	actions := make([]string, 0, len(StatusTransitions))
+	for action := range StatusTransitions {
+		actions = append(actions, action)
+	}
+	slices.Sort(actions)
+
+	paths := map[string][]string{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+		if status == to {
+			return paths[status], true
+		}
+		for _, action := range actions {
+			transition := StatusTransitions[action]
+			if _, seen := paths[transition.To]; seen || !transition.Allows(status) {
+				continue
+			}
+			paths[transition.To] = append(slices.Clone(paths[status]), action)
+			queue = append(queue, transition.To)
+		}
+	}
+	return nil, false
+}
+
+type StatusTransitionPayload struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// CertificateStatusChange is one entry of a certificate's status history.
+// FromStatus is empty for the first recorded status and ActorXID is empty
+// for changes made by the worker.
+type CertificateStatusChange struct {
+	ID         int64     `json:"id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ActorXID   string    `json:"actor_xid,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
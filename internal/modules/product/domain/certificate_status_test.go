@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStatusTransitionAllows(t *testing.T) {
+	tests := []struct {
+		action string
+		from   string
+		want   bool
+	}{
+		{TransitionApprove, StatusPending, true},
+		{TransitionApprove, StatusValid, false},
+		{TransitionApprove, StatusSuspended, false},
+		{TransitionSuspend, StatusValid, true},
+		{TransitionSuspend, StatusPending, false},
+		{TransitionSuspend, StatusExpired, false},
+		{TransitionReinstate, StatusSuspended, true},
+		{TransitionReinstate, StatusRevoked, false},
+		{TransitionRevoke, StatusPending, true},
+		{TransitionRevoke, StatusValid, true},
+		{TransitionRevoke, StatusSuspended, true},
+		{TransitionRevoke, StatusExpired, true},
+		{TransitionRevoke, StatusRevoked, false},
+	}
+	for _, tt := range tests {
+		if got := StatusTransitions[tt.action].Allows(tt.from); got != tt.want {
+			t.Errorf("%s from %s: Allows = %v, want %v", tt.action, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestRevokedIsTerminal(t *testing.T) {
+	for action, transition := range StatusTransitions {
+		if transition.Allows(StatusRevoked) {
+			t.Errorf("%s leaves revoked", action)
+		}
+	}
+}
+
+func TestTransitionPath(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     []string
+		ok       bool
+	}{
+		{StatusPending, StatusPending, []string{}, true},
+		{StatusPending, StatusValid, []string{TransitionApprove}, true},
+		{StatusPending, StatusSuspended, []string{TransitionApprove, TransitionSuspend}, true},
+		{StatusSuspended, StatusValid, []string{TransitionReinstate}, true},
+		{StatusValid, StatusRevoked, []string{TransitionRevoke}, true},
+		{StatusExpired, StatusRevoked, []string{TransitionRevoke}, true},
+		{StatusValid, StatusExpired, nil, false},
+		{StatusExpired, StatusValid, nil, false},
+		{StatusRevoked, StatusValid, nil, false},
+		{StatusValid, StatusPending, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := TransitionPath(tt.from, tt.to)
+		if ok != tt.ok || !slices.Equal(got, tt.want) {
+			t.Errorf("TransitionPath(%s, %s) = %v, %v; want %v, %v", tt.from, tt.to, got, ok, tt.want, tt.ok)
+		}
+	}
+}
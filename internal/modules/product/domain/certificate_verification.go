@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+const (
+	VerdictValid     = "valid"
+	VerdictExpired   = "expired"
+	VerdictRevoked   = "revoked"
+	VerdictSuspended = "suspended"
+	VerdictPending   = "pending"
+	// VerdictSuperseded answers for a number a renewal replaced.
+	VerdictSuperseded = "superseded"
+)
+
+// CertificateVerification is the public answer to "is this certificate genuine
+// and currently in force?".
+type CertificateVerification struct {
+	Verdict     string                   `json:"verdict"`
+	Valid       bool                     `json:"valid"`
+	Message     string                   `json:"message"`
+	Certificate PublicProgramCertificate `json:"certificate"`
+	// SupersededBy is the current number of a renewed certificate, set with
+	// VerdictSuperseded; Certificate then shows the current terms.
+	SupersededBy string     `json:"superseded_by,omitempty"`
+	RenewedAt    *time.Time `json:"renewed_at,omitempty"`
+	CheckedAt    time.Time  `json:"checked_at"`
+}
+
+// NewCertificateVerification derives the verdict from the certificate status
+// and its expiry date as of now. A past expiry date wins over a stale "valid"
+// status, while a revocation always wins.
+func NewCertificateVerification(record ProgramCertificate, now time.Time) CertificateVerification {
+	statusCode := ""
+	if record.Status != nil {
+		statusCode = record.Status.Code
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	expired := record.ExpiryDate != nil && record.ExpiryDate.Before(today)
+
+	verdict := VerdictPending
+	switch {
+	case statusCode == VerdictRevoked:
+		verdict = VerdictRevoked
+	case expired || statusCode == VerdictExpired:
+		verdict = VerdictExpired
+	case statusCode == VerdictSuspended:
+		verdict = VerdictSuspended
+	case statusCode == VerdictValid:
+		verdict = VerdictValid
+	}
+
+	return CertificateVerification{
+		Verdict:     verdict,
+		Valid:       verdict == VerdictValid,
+		Message:     verdictMessages[verdict],
+		Certificate: NewPublicProgramCertificate(record),
+		CheckedAt:   now,
+	}
+}
+
+// NewSupersededVerification answers for a number that renewal replaced on
+// current, pointing the verifier at the current certificate.
+func NewSupersededVerification(renewal CertificateRenewal, current ProgramCertificate, now time.Time) CertificateVerification {
+	renewedAt := renewal.RenewedAt
+	return CertificateVerification{
+		Verdict:      VerdictSuperseded,
+		Valid:        false,
+		Message:      "certificate was renewed and replaced by certificate " + current.CertificateNo,
+		Certificate:  NewPublicProgramCertificate(current),
+		SupersededBy: current.CertificateNo,
+		RenewedAt:    &renewedAt,
+		CheckedAt:    now,
+	}
+}
+
+var verdictMessages = map[string]string{
+	VerdictValid:     "certificate is genuine and currently valid",
+	VerdictExpired:   "certificate is genuine but has expired",
+	VerdictRevoked:   "certificate has been revoked",
+	VerdictSuspended: "certificate is currently suspended",
+	VerdictPending:   "certificate has not been issued yet",
+}
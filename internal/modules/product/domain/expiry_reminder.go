@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ExpiryReminder is a pending "certificate expires soon" notice for one
+// threshold (e.g. 30 days before expiry) of one certificate.
+type ExpiryReminder struct {
+	CertificateID     int64
+	DaysBefore        int
+	ExpiryDate        time.Time
+	CertificateNo     string
+	ProgramName       string
+	CertificationName string
+	ProductName       string
+	CompanyName       string
+	CompanyEmail      string
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
+)
 
 type Product struct {
 	ID        int64          `json:"id"`
@@ -19,6 +23,25 @@ type Product struct {
 // Brand     Brand          `json:"brand"`
 // Company   Company        `json:"company"`
 
+// ProductExport is a product together with the names of its program, brand
+// and company, as written to product exports.
+type ProductExport struct {
+	Product
+	ProgramCode   string
+	Brand         string
+	BrandCategory string
+	Company       string
+}
+
+// ProductCursor walks over products one row at a time, for exports that
+// should not load the whole list into memory.
+type ProductCursor interface {
+	Next() bool
+	Product() (ProductExport, error)
+	Err() error
+	Close() error
+}
+
 type ProductFilter struct {
 	ProgramCode  string
 	BrandSlug    string
@@ -27,4 +50,8 @@ type ProductFilter struct {
 	IsActiveOnly bool
 	Page         int
 	PageSize     int
+	// Cursor continues after the last product of the previous page and
+	// replaces Page.
+	Cursor    *pagination.Cursor
+	SkipTotal bool
 }
@@ -1,8 +1,9 @@
 package domain
 
 type ProductListResponse struct {
-	Items    []Product `json:"items"`
-	Total    int       `json:"total"`
-	Page     int       `json:"page"`
-	PageSize int       `json:"page_size"`
+	Items      []Product `json:"items"`
+	Total      *int      `json:"total,omitempty"`
+	Page       int       `json:"page,omitempty"`
+	PageSize   int       `json:"page_size"`
+	NextCursor string    `json:"next_cursor,omitempty"`
 }
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
+)
 
 type ProgramCertificate struct {
 	ID            int64                `json:"id"`
@@ -44,17 +48,71 @@ type ProgramCompany struct {
 	Slug string `json:"slug"`
 }
 
+// Sort fields accepted by program certificate listings. SortUpdatedAt is
+// the default and the only order cursor pagination supports.
+const (
+	SortUpdatedAt   = "updated_at"
+	SortExpiryDate  = "expiry_date"
+	SortIssueDate   = "issue_date"
+	SortProductName = "product_name"
+	SortCompany     = "company"
+)
+
+// ProgramCertificateSorts lists the accepted sort fields.
+var ProgramCertificateSorts = []string{SortUpdatedAt, SortExpiryDate, SortIssueDate, SortProductName, SortCompany}
+
+// MaxExpiringWithinDays bounds the expiring_within filter to ten years.
+const MaxExpiringWithinDays = 3650
+
 type ProgramCertificateFilter struct {
-	Search   string
-	Page     int
-	PageSize int
+	Search             string
+	ValidOnly          bool
+	ActiveProductsOnly bool
+	StatusCode         string
+	CertificationID    int64
+	BrandSlug          string
+	BrandCategorySlug  string
+	CompanySlug        string
+	// Date ranges are inclusive; either end may be left open.
+	IssueFrom  *time.Time
+	IssueTo    *time.Time
+	ExpiryFrom *time.Time
+	ExpiryTo   *time.Time
+	// ExpiringWithinDays keeps certificates expiring between today and that
+	// many days from now, at most MaxExpiringWithinDays.
+	ExpiringWithinDays *int
+	// Sort is one of ProgramCertificateSorts; empty means SortUpdatedAt.
+	// Rows are sorted descending unless SortAscending is set.
+	Sort          string
+	SortAscending bool
+	Page          int
+	PageSize      int
+	// Cursor continues after the last certificate of the previous page and
+	// replaces Page.
+	Cursor    *pagination.Cursor
+	SkipTotal bool
+}
+
+// Keyset reports whether the filter's order supports cursor pagination.
+func (f ProgramCertificateFilter) Keyset() bool {
+	return (f.Sort == "" || f.Sort == SortUpdatedAt) && !f.SortAscending
 }
 
 type ProgramCertificateListResponse struct {
-	Items    []ProgramCertificate `json:"items"`
-	Total    int                  `json:"total"`
-	Page     int                  `json:"page"`
-	PageSize int                  `json:"page_size"`
+	Items      []ProgramCertificate `json:"items"`
+	Total      *int                 `json:"total,omitempty"`
+	Page       int                  `json:"page,omitempty"`
+	PageSize   int                  `json:"page_size"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// ProgramCertificateCursor walks over program certificates one row at a
+// time, for exports that should not load the whole list into memory.
+type ProgramCertificateCursor interface {
+	Next() bool
+	Certificate() (ProgramCertificate, error)
+	Err() error
+	Close() error
 }
 
 type ProgramCertificatePayload struct {
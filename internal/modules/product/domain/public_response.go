@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// PublicProduct is the product shape exposed to unauthenticated clients.
+type PublicProduct struct {
+	Name     string         `json:"name"`
+	Slug     string         `json:"slug"`
+	Features string         `json:"features,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+	TSHP     map[string]any `json:"tshp"`
+	Images   []string       `json:"images"`
+}
+
+// PublicProgramCertificate is the certificate shape exposed to unauthenticated
+// clients. It leaves out meta_json, document paths and internal timestamps.
+type PublicProgramCertificate struct {
+	Program       PublicProgram        `json:"program"`
+	Product       PublicNamedRef       `json:"product"`
+	Brand         PublicNamedRef       `json:"brand"`
+	Company       PublicNamedRef       `json:"company"`
+	Certification PublicCertification  `json:"certification"`
+	CertificateNo string               `json:"certificate_no,omitempty"`
+	IssueDate     *time.Time           `json:"issue_date,omitempty"`
+	ExpiryDate    *time.Time           `json:"expiry_date,omitempty"`
+	Status        *CertificationStatus `json:"status,omitempty"`
+}
+
+type PublicProgram struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type PublicNamedRef struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type PublicCertification struct {
+	Name  string `json:"name"`
+	Image string `json:"image,omitempty"`
+}
+
+func NewPublicProduct(product Product) PublicProduct {
+	return PublicProduct{
+		Name:     product.Name,
+		Slug:     product.Slug,
+		Features: product.Features,
+		Reason:   product.Reason,
+		TSHP:     product.TSHP,
+		Images:   product.Images,
+	}
+}
+
+func NewPublicProgramCertificate(record ProgramCertificate) PublicProgramCertificate {
+	return PublicProgramCertificate{
+		Program:       PublicProgram{Code: record.Program.Code, Name: record.Program.Name},
+		Product:       PublicNamedRef{Name: record.Product.Name, Slug: record.Product.Slug},
+		Brand:         PublicNamedRef{Name: record.Brand.Name, Slug: record.Brand.Slug},
+		Company:       PublicNamedRef{Name: record.Company.Name, Slug: record.Company.Slug},
+		Certification: PublicCertification{Name: record.Certification.Name, Image: record.Certification.Image},
+		CertificateNo: record.CertificateNo,
+		IssueDate:     record.IssueDate,
+		ExpiryDate:    record.ExpiryDate,
+		Status:        record.Status,
+	}
+}
@@ -0,0 +1,15 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is implemented by *sql.DB and *sql.Tx. The package-level write
+// functions take one so other modules can run them inside their own
+// transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
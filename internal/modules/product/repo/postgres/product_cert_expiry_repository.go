@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+)
+
+// ExpireCertificates moves valid certificates whose expiry date is before
+// runDate to expired, adds a status history entry for each, and records the
+// run. Already expired rows are skipped, so repeated runs on the same day
+// only add zero to the recorded count.
+func (repository *ProductCertificationRepository) ExpireCertificates(ctx context.Context, runDate time.Time) (int64, error) {
+	const expireQuery = `
+WITH statuses AS (
+    SELECT
+        (SELECT id FROM public.lkp_cert_status WHERE code = 'valid') AS valid_id,
+        (SELECT id FROM public.lkp_cert_status WHERE code = 'expired') AS expired_id
+),
+expired AS (
+    UPDATE public.product_has_certification pc
+    SET status_id = statuses.expired_id,
+        updated_at = NOW()
+    FROM statuses
+    WHERE pc.status_id = statuses.valid_id
+      AND pc.expiry_date IS NOT NULL
+      AND pc.expiry_date < $1
+    RETURNING pc.id
+)
+INSERT INTO public.certificate_status_history (certificate_id, from_status_id, to_status_id, reason)
+SELECT expired.id, statuses.valid_id, statuses.expired_id, 'expiry date passed'
+FROM expired, statuses`
+
+	const recordQuery = `
+INSERT INTO public.certificate_expiry_runs (run_date, expired_count, run_count)
+VALUES ($1, $2, 1)
+ON CONFLICT (run_date) DO UPDATE
+SET expired_count = certificate_expiry_runs.expired_count + EXCLUDED.expired_count,
+    run_count = certificate_expiry_runs.run_count + 1,
+    last_run_at = NOW()`
+
+	date := runDate.Format(time.DateOnly)
+
+	var expired int64
+	err := db.WithTx(repository.DB, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, expireQuery, date)
+		if err != nil {
+			return err
+		}
+		if expired, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, recordQuery, date, expired)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// ListDueExpiryReminders returns, for every valid certificate expiring on or
+// after runDate, the smallest threshold it has already crossed, unless that
+// reminder was sent for the current expiry date. A certificate created five
+// days before expiry therefore only gets the 7-day reminder.
+func (repository *ProductCertificationRepository) ListDueExpiryReminders(ctx context.Context, runDate time.Time, daysBefore []int) ([]domain.ExpiryReminder, error) {
+	const query = `
+SELECT
+    pc.id,
+    t.days_before,
+    pc.expiry_date,
+    COALESCE(pc.certificate_no, ''),
+    prog.name,
+    c.name,
+    p.name,
+    co.name,
+    COALESCE(co.email, '')
+FROM public.product_has_certification pc
+JOIN public.lkp_cert_status cs ON cs.id = pc.status_id
+JOIN public.certifications c ON c.id = pc.certification_id
+JOIN public.lkp_product_program prog ON prog.id = c.program_id
+JOIN public.products p ON p.id = pc.product_id
+JOIN public.companies co ON co.id = p.company_id
+CROSS JOIN LATERAL (
+    SELECT MIN(d) AS days_before
+    FROM unnest($2::int[]) AS d
+    WHERE d >= pc.expiry_date - $1::date
+) t
+WHERE cs.code = 'valid'
+  AND pc.expiry_date >= $1::date
+  AND t.days_before IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1
+      FROM public.certificate_expiry_reminders r
+      WHERE r.certificate_id = pc.id
+        AND r.days_before = t.days_before
+        AND r.expiry_date = pc.expiry_date
+  )
+ORDER BY pc.expiry_date, pc.id`
+
+	thresholds := make([]int32, 0, len(daysBefore))
+	for _, days := range daysBefore {
+		thresholds = append(thresholds, int32(days))
+	}
+
+	rows, err := repository.DB.QueryContext(ctx, query, runDate.Format(time.DateOnly), thresholds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []domain.ExpiryReminder
+	for rows.Next() {
+		var reminder domain.ExpiryReminder
+		if err := rows.Scan(
+			&reminder.CertificateID,
+			&reminder.DaysBefore,
+			&reminder.ExpiryDate,
+			&reminder.CertificateNo,
+			&reminder.ProgramName,
+			&reminder.CertificationName,
+			&reminder.ProductName,
+			&reminder.CompanyName,
+			&reminder.CompanyEmail,
+		); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, rows.Err()
+}
+
+// ClaimExpiryReminder records the reminder as sent. It reports false when
+// another run already claimed it.
+func (repository *ProductCertificationRepository) ClaimExpiryReminder(ctx context.Context, reminder domain.ExpiryReminder) (bool, error) {
+	const query = `
+INSERT INTO public.certificate_expiry_reminders (certificate_id, days_before, expiry_date)
+VALUES ($1, $2, $3)
+ON CONFLICT (certificate_id, days_before, expiry_date) DO NOTHING`
+
+	result, err := repository.DB.ExecContext(ctx, query, reminder.CertificateID, reminder.DaysBefore, reminder.ExpiryDate.Format(time.DateOnly))
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ReleaseExpiryReminder drops a claim so the next run retries the reminder.
+func (repository *ProductCertificationRepository) ReleaseExpiryReminder(ctx context.Context, reminder domain.ExpiryReminder) error {
+	const query = `
+DELETE FROM public.certificate_expiry_reminders
+WHERE certificate_id = $1 AND days_before = $2 AND expiry_date = $3`
+
+	_, err := repository.DB.ExecContext(ctx, query, reminder.CertificateID, reminder.DaysBefore, reminder.ExpiryDate.Format(time.DateOnly))
+	return err
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// RenewCertificate archives the current terms of a certificate, replaces them
+// with the renewed ones, marks the certificate valid and records the status
+// change. It reports false without changing anything when the certificate is
+// no longer in the from status.
+func (repository *ProductCertificationRepository) RenewCertificate(
+	ctx context.Context,
+	certificateID int64,
+	from string,
+	payload domain.CertificateRenewalPayload,
+	actorXID string,
+) (bool, error) {
+	const lockQuery = `
+SELECT cs.code
+FROM public.product_has_certification pc
+LEFT JOIN public.lkp_cert_status cs ON cs.id = pc.status_id
+WHERE pc.id = $1
+FOR UPDATE OF pc`
+
+	const archiveQuery = `
+INSERT INTO public.certificate_renewals (certificate_id, certificate_no, issue_date, expiry_date, status_id, document_file, meta_json, renewed_by)
+SELECT id, certificate_no, issue_date, expiry_date, status_id, document_file, meta_json, $2
+FROM public.product_has_certification
+WHERE id = $1`
+
+	const renewQuery = `
+UPDATE public.product_has_certification
+SET certificate_no = $2,
+    issue_date = $3,
+    expiry_date = $4,
+    document_file = $5,
+    status_id = (SELECT id FROM public.lkp_cert_status WHERE code = 'valid'),
+    updated_at = NOW()
+WHERE id = $1`
+
+	const historyQuery = `
+INSERT INTO public.certificate_status_history (certificate_id, from_status_id, to_status_id, reason, actor_xid)
+VALUES (
+    $1,
+    (SELECT id FROM public.lkp_cert_status WHERE code = $2),
+    (SELECT id FROM public.lkp_cert_status WHERE code = 'valid'),
+    $3,
+    $4
+)`
+
+	var actor any
+	if actorXID != "" {
+		actor = actorXID
+	}
+	var documentFile any
+	if payload.DocumentFile != nil {
+		documentFile = *payload.DocumentFile
+	}
+	reason := payload.Reason
+	if reason == "" {
+		reason = "renewed"
+	}
+
+	applied := false
+	err := db.WithTx(repository.DB, func(tx *sql.Tx) error {
+		var current sql.NullString
+		if err := tx.QueryRowContext(ctx, lockQuery, certificateID).Scan(&current); err != nil {
+			return err
+		}
+		if current.String != from {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, archiveQuery, certificateID, actor); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, renewQuery, certificateID, payload.CertificateNo, payload.IssueDate, payload.ExpiryDate, documentFile); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, historyQuery, certificateID, from, reason, actor); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// FindRenewalByNumber returns the most recent renewal that replaced the
+// certificate number, with the ID of the certificate it belongs to.
+func (repository *ProductCertificationRepository) FindRenewalByNumber(ctx context.Context, certificateNo string) (int64, domain.CertificateRenewal, error) {
+	const query = `
+SELECT r.certificate_id, r.id, r.certificate_no, r.renewed_at
+FROM public.certificate_renewals r
+WHERE UPPER(r.certificate_no) = UPPER($1)
+ORDER BY r.renewed_at DESC, r.id DESC
+LIMIT 1`
+
+	var (
+		certificateID int64
+		renewal       domain.CertificateRenewal
+	)
+	err := repository.DB.QueryRowContext(ctx, query, certificateNo).Scan(&certificateID, &renewal.ID, &renewal.CertificateNo, &renewal.RenewedAt)
+	if err != nil {
+		return 0, domain.CertificateRenewal{}, err
+	}
+	return certificateID, renewal, nil
+}
+
+func (repository *ProductCertificationRepository) ListCertificateRenewals(ctx context.Context, certificateID int64) ([]domain.CertificateRenewal, error) {
+	const query = `
+SELECT r.id, r.certificate_no, r.issue_date, r.expiry_date, cs.code, r.document_file, r.renewed_by, r.renewed_at
+FROM public.certificate_renewals r
+LEFT JOIN public.lkp_cert_status cs ON cs.id = r.status_id
+WHERE r.certificate_id = $1
+ORDER BY r.renewed_at DESC, r.id DESC`
+
+	rows, err := repository.DB.QueryContext(ctx, query, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	renewals := []domain.CertificateRenewal{}
+	for rows.Next() {
+		var (
+			renewal       domain.CertificateRenewal
+			certificateNo sql.NullString
+			issue         sql.NullTime
+			expiry        sql.NullTime
+			status        sql.NullString
+			documentFile  sql.NullString
+			renewedBy     sql.NullString
+		)
+		if err := rows.Scan(&renewal.ID, &certificateNo, &issue, &expiry, &status, &documentFile, &renewedBy, &renewal.RenewedAt); err != nil {
+			return nil, err
+		}
+		renewal.CertificateNo = certificateNo.String
+		if issue.Valid {
+			t := issue.Time
+			renewal.IssueDate = &t
+		}
+		if expiry.Valid {
+			t := expiry.Time
+			renewal.ExpiryDate = &t
+		}
+		renewal.Status = status.String
+		renewal.DocumentFile = documentFile.String
+		renewal.RenewedBy = renewedBy.String
+		renewals = append(renewals, renewal)
+	}
+	return renewals, rows.Err()
+}
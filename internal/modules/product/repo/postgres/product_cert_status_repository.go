@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// TransitionCertificateStatus moves a certificate from one status to another
+// and appends the change to its history. It reports false without changing
+// anything when the certificate is no longer in the from status, so two
+// concurrent transitions cannot both apply.
+func (repository *ProductCertificationRepository) TransitionCertificateStatus(
+	ctx context.Context,
+	certificateID int64,
+	from, to, reason, actorXID string,
+) (bool, error) {
+	applied := false
+	err := db.WithTx(repository.DB, func(tx *sql.Tx) error {
+		var err error
+		applied, err = TransitionStatus(ctx, tx, certificateID, from, to, reason, actorXID)
+		return err
+	})
+	return applied, err
+}
+
+// TransitionStatus is TransitionCertificateStatus on db, which must be a
+// transaction for the update and its history entry to be atomic.
+func TransitionStatus(ctx context.Context, db DBTX, certificateID int64, from, to, reason, actorXID string) (bool, error) {
+	const updateQuery = `
+UPDATE public.product_has_certification
+SET status_id = (SELECT id FROM public.lkp_cert_status WHERE code = $3),
+    updated_at = NOW()
+WHERE id = $1
+  AND status_id = (SELECT id FROM public.lkp_cert_status WHERE code = $2)`
+
+	const historyQuery = `
+INSERT INTO public.certificate_status_history (certificate_id, from_status_id, to_status_id, reason, actor_xid)
+VALUES (
+    $1,
+    (SELECT id FROM public.lkp_cert_status WHERE code = $2),
+    (SELECT id FROM public.lkp_cert_status WHERE code = $3),
+    $4,
+    $5
+)`
+
+	result, err := db.ExecContext(ctx, updateQuery, certificateID, from, to)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	if _, err := db.ExecContext(ctx, historyQuery, certificateID, from, to, reason, nullableActor(actorXID)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// nullableActor stores worker changes, which have no actor, as NULL.
+func nullableActor(actorXID string) any {
+	if actorXID == "" {
+		return nil
+	}
+	return actorXID
+}
+
+func (repository *ProductCertificationRepository) ListCertificateStatusHistory(ctx context.Context, certificateID int64) ([]domain.CertificateStatusChange, error) {
+	const query = `
+SELECT h.id, fs.code, ts.code, h.reason, h.actor_xid, h.created_at
+FROM public.certificate_status_history h
+LEFT JOIN public.lkp_cert_status fs ON fs.id = h.from_status_id
+JOIN public.lkp_cert_status ts ON ts.id = h.to_status_id
+WHERE h.certificate_id = $1
+ORDER BY h.created_at, h.id`
+
+	rows, err := repository.DB.QueryContext(ctx, query, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.CertificateStatusChange{}
+	for rows.Next() {
+		var (
+			change     domain.CertificateStatusChange
+			fromStatus sql.NullString
+			actorXID   sql.NullString
+		)
+		if err := rows.Scan(&change.ID, &fromStatus, &change.ToStatus, &change.Reason, &actorXID, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+		change.FromStatus = fromStatus.String
+		change.ActorXID = actorXID.String
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
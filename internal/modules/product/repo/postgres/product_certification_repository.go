@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/Nassabiq/gpci-compro-api/internal/db"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
 )
 
@@ -95,19 +96,37 @@ func (repository *ProductCertificationRepository) ListProductCertifications(ctx
 	return result, total, nil
 }
 
-func (repository *ProductCertificationRepository) CreateProductCertification(ctx context.Context, productSlug string, payload domain.ProductCertificationPayload) (domain.ProductCertification, error) {
+// CreateProductCertification inserts a certificate in domain.InitialStatus
+// and records that status as the first entry of its history in the same
+// transaction.
+func (repository *ProductCertificationRepository) CreateProductCertification(ctx context.Context, productSlug string, payload domain.ProductCertificationPayload, actorXID string) (domain.ProductCertification, error) {
 	productID, err := repository.productIDBySlug(ctx, productSlug)
 	if err != nil {
 		return domain.ProductCertification{}, err
 	}
 
+	err = db.WithTx(repository.DB, func(tx *sql.Tx) error {
+		_, err := InsertCertificate(ctx, tx, productID, payload, actorXID)
+		return err
+	})
+	if err != nil {
+		return domain.ProductCertification{}, err
+	}
+
+	return repository.getProductCertification(ctx, productID, payload.CertificationID)
+}
+
+// InsertCertificate writes a certificate in domain.InitialStatus together
+// with its first status history entry and returns its ID. payload.StatusID is
+// ignored. db must be a transaction for the two writes to be atomic.
+func InsertCertificate(ctx context.Context, db DBTX, productID int64, payload domain.ProductCertificationPayload, actorXID string) (int64, error) {
 	meta := payload.Meta
 	if meta == nil {
 		meta = map[string]any{}
 	}
 	metaJSON, err := json.Marshal(meta)
 	if err != nil {
-		return domain.ProductCertification{}, err
+		return 0, err
 	}
 
 	var certificateNo any
@@ -129,9 +148,17 @@ func (repository *ProductCertificationRepository) CreateProductCertification(ctx
 			status_id,
 			document_file,
 			meta_json
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8::jsonb)`
+		) VALUES ($1,$2,$3,$4,$5,(SELECT id FROM public.lkp_cert_status WHERE code = $6),$7,$8::jsonb)
+		RETURNING id`
 
-	_, err = repository.DB.ExecContext(
+	const historyQuery = `
+		INSERT INTO public.certificate_status_history (certificate_id, to_status_id, reason, actor_xid)
+		SELECT id, status_id, 'created', $2
+		FROM public.product_has_certification
+		WHERE id = $1`
+
+	var certificateID int64
+	if err := db.QueryRowContext(
 		ctx,
 		query,
 		productID,
@@ -139,15 +166,16 @@ func (repository *ProductCertificationRepository) CreateProductCertification(ctx
 		certificateNo,
 		payload.IssueDate,
 		payload.ExpiryDate,
-		payload.StatusID,
+		domain.InitialStatus,
 		documentFile,
 		metaJSON,
-	)
-	if err != nil {
-		return domain.ProductCertification{}, err
+	).Scan(&certificateID); err != nil {
+		return 0, err
 	}
-
-	return repository.getProductCertification(ctx, productID, payload.CertificationID)
+	if _, err := db.ExecContext(ctx, historyQuery, certificateID, nullableActor(actorXID)); err != nil {
+		return 0, err
+	}
+	return certificateID, nil
 }
 
 func (repository *ProductCertificationRepository) UpdateProductCertification(
@@ -156,7 +184,6 @@ func (repository *ProductCertificationRepository) UpdateProductCertification(
 	certificationID int64,
 	payload domain.ProductCertificationPayload,
 ) (domain.ProductCertification, error) {
-	// BEGIN FUNCTION
 	productID, err := repository.productIDBySlug(ctx, productSlug)
 	if err != nil {
 		return domain.ProductCertification{}, err
@@ -185,9 +212,8 @@ func (repository *ProductCertificationRepository) UpdateProductCertification(
 		SET certificate_no = $3,
 			issue_date = $4,
 			expiry_date = $5,
-			status_id = $6,
-			document_file = $7,
-			meta_json = $8::jsonb,
+			document_file = $6,
+			meta_json = $7::jsonb,
 			updated_at = NOW()
 		WHERE product_id = $1 AND certification_id = $2`
 
@@ -199,7 +225,6 @@ func (repository *ProductCertificationRepository) UpdateProductCertification(
 		certificateNo,
 		payload.IssueDate,
 		payload.ExpiryDate,
-		payload.StatusID,
 		documentFile,
 		metaJSON,
 	); err != nil {
@@ -209,6 +234,14 @@ func (repository *ProductCertificationRepository) UpdateProductCertification(
 	return repository.getProductCertification(ctx, productID, certificationID)
 }
 
+func (repository *ProductCertificationRepository) GetProductCertification(ctx context.Context, productSlug string, certificationID int64) (domain.ProductCertification, error) {
+	productID, err := repository.productIDBySlug(ctx, productSlug)
+	if err != nil {
+		return domain.ProductCertification{}, err
+	}
+	return repository.getProductCertification(ctx, productID, certificationID)
+}
+
 func (repository *ProductCertificationRepository) DeleteProductCertification(ctx context.Context, productSlug string, certificationID int64) error {
 	productID, err := repository.productIDBySlug(ctx, productSlug)
 	if err != nil {
@@ -6,15 +6,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
 )
 
 type programRowScanner interface {
 	Scan(dest ...any) error
 }
 
-const programCertificateBaseSelect = `
+const programCertificateFrom = `
+FROM public.product_has_certification pc
+JOIN public.products p ON p.id = pc.product_id
+JOIN public.certifications c ON c.id = pc.certification_id
+JOIN public.lkp_product_program prog ON prog.id = c.program_id
+JOIN public.brands b ON b.id = p.brand_id
+JOIN public.brand_categories bc ON bc.id = b.brand_category_id
+JOIN public.companies co ON co.id = p.company_id
+LEFT JOIN public.lkp_cert_status cs ON cs.id = pc.status_id
+`
+
+const programCertificateSelect = `
 SELECT
 	pc.id,
 	pc.created_at,
@@ -44,67 +57,58 @@ SELECT
 	prog.name,
 	cs.id,
 	cs.code,
-	cs.name
-FROM public.product_has_certification pc
-JOIN public.products p ON p.id = pc.product_id
-JOIN public.certifications c ON c.id = pc.certification_id
-JOIN public.lkp_product_program prog ON prog.id = c.program_id
-JOIN public.brands b ON b.id = p.brand_id
-JOIN public.brand_categories bc ON bc.id = b.brand_category_id
-JOIN public.companies co ON co.id = p.company_id
-LEFT JOIN public.lkp_cert_status cs ON cs.id = pc.status_id
-WHERE prog.code = $1
+	cs.name` + programCertificateFrom
+
+const programCertificateBaseSelect = programCertificateSelect + `WHERE prog.code = $1
 `
 
-func (repository *ProductCertificationRepository) ListProgramCertificates(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) ([]domain.ProgramCertificate, int, error) {
-	countBuilder := strings.Builder{}
-	countBuilder.WriteString(`
-		SELECT COUNT(*)
-		FROM public.product_has_certification pc
-		JOIN public.products p ON p.id = pc.product_id
-		JOIN public.certifications c ON c.id = pc.certification_id
-		JOIN public.lkp_product_program prog ON prog.id = c.program_id
-		JOIN public.brands b ON b.id = p.brand_id
-		JOIN public.brand_categories bc ON bc.id = b.brand_category_id
-		JOIN public.companies co ON co.id = p.company_id
-		WHERE prog.code = $1`)
+// ListProgramCertificates returns a page of a program's certificates, most
+// recently updated first. A cursor in the filter replaces the offset, and the
+// total is only counted unless SkipTotal is set.
+func (repository *ProductCertificationRepository) ListProgramCertificates(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) ([]domain.ProgramCertificate, pagination.Result, error) {
+	var result pagination.Result
+	args := []any{programCode}
+	conditions := buildProgramCertificateConditions(filter, &args)
 
-	countArgs := []any{programCode}
-	if filter.Search != "" {
-		countArgs = append(countArgs, fmt.Sprintf("%%%s%%", filter.Search))
-		countBuilder.WriteString(fmt.Sprintf(" AND (p.name ILIKE $%d OR coalesce(pc.certificate_no,'') ILIKE $%d OR co.name ILIKE $%d OR c.name ILIKE $%d)", len(countArgs), len(countArgs), len(countArgs), len(countArgs)))
-	}
+	if !filter.SkipTotal {
+		countBuilder := strings.Builder{}
+		countBuilder.WriteString("\nSELECT COUNT(*)")
+		countBuilder.WriteString(programCertificateFrom)
+		countBuilder.WriteString("WHERE prog.code = $1")
+		countBuilder.WriteString(conditions)
 
-	var total int
-	if err := repository.DB.QueryRowContext(ctx, countBuilder.String(), countArgs...).Scan(&total); err != nil {
-		return nil, 0, err
+		var total int
+		if err := repository.DB.QueryRowContext(ctx, countBuilder.String(), args...).Scan(&total); err != nil {
+			return nil, result, err
+		}
+		result.Total = &total
 	}
 
 	limit := filter.PageSize
 	offset := 0
-	if filter.Page > 0 && filter.PageSize > 0 {
+	if filter.Cursor == nil && filter.Page > 0 && filter.PageSize > 0 {
 		offset = (filter.Page - 1) * filter.PageSize
 	}
-	if limit > 0 && offset >= total {
-		return []domain.ProgramCertificate{}, total, nil
+	if limit > 0 && result.Total != nil && offset >= *result.Total {
+		return []domain.ProgramCertificate{}, result, nil
 	}
 
-	var (
-		args    = []any{programCode}
-		builder strings.Builder
-	)
+	order, err := programCertificateOrder(filter)
+	if err != nil {
+		return nil, result, err
+	}
 
+	var builder strings.Builder
 	builder.WriteString(programCertificateBaseSelect)
-
-	if filter.Search != "" {
-		args = append(args, fmt.Sprintf("%%%s%%", filter.Search))
-		builder.WriteString(fmt.Sprintf(" AND (p.name ILIKE $%d OR co.name ILIKE $%d OR c.name ILIKE $%d OR coalesce(pc.certificate_no,'') ILIKE $%d)", len(args), len(args), len(args), len(args)))
+	builder.WriteString(conditions)
+	if filter.Cursor != nil {
+		builder.WriteString(" AND ")
+		builder.WriteString(filter.Cursor.Condition("pc.updated_at", "pc.id", &args))
 	}
-
-	builder.WriteString("\nORDER BY pc.updated_at DESC, pc.id DESC")
+	builder.WriteString(order)
 
 	if limit > 0 {
-		args = append(args, limit)
+		args = append(args, limit+1)
 		builder.WriteString(fmt.Sprintf("\nLIMIT $%d", len(args)))
 		if offset > 0 {
 			args = append(args, offset)
@@ -114,7 +118,7 @@ func (repository *ProductCertificationRepository) ListProgramCertificates(ctx co
 
 	rows, err := repository.DB.QueryContext(ctx, builder.String(), args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, result, err
 	}
 	defer rows.Close()
 
@@ -122,14 +126,151 @@ func (repository *ProductCertificationRepository) ListProgramCertificates(ctx co
 	for rows.Next() {
 		record, scanErr := scanProgramCertificate(rows)
 		if scanErr != nil {
-			return nil, 0, scanErr
+			return nil, result, scanErr
 		}
 		certificates = append(certificates, record)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, result, err
+	}
+
+	certificates, result.Next = pagination.Trim(certificates, limit, func(record domain.ProgramCertificate) pagination.Cursor {
+		return pagination.Cursor{Time: record.UpdatedAt, ID: record.ID}
+	})
+	if !filter.Keyset() {
+		result.Next = nil
+	}
+	return certificates, result, nil
+}
+
+// StreamProgramCertificates runs the list query without paging and returns a
+// cursor over its rows, in the same order as ListProgramCertificates.
+func (repository *ProductCertificationRepository) StreamProgramCertificates(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) (domain.ProgramCertificateCursor, error) {
+	args := []any{programCode}
+	conditions := buildProgramCertificateConditions(filter, &args)
+
+	order, err := programCertificateOrder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(programCertificateBaseSelect)
+	builder.WriteString(conditions)
+	builder.WriteString(order)
+
+	rows, err := repository.DB.QueryContext(ctx, builder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	return &programCertificateCursor{rows: rows}, nil
+}
+
+type programCertificateCursor struct {
+	rows *sql.Rows
+}
+
+func (cursor *programCertificateCursor) Next() bool {
+	return cursor.rows.Next()
+}
+
+func (cursor *programCertificateCursor) Certificate() (domain.ProgramCertificate, error) {
+	return scanProgramCertificate(cursor.rows)
+}
+
+func (cursor *programCertificateCursor) Err() error {
+	return cursor.rows.Err()
+}
+
+func (cursor *programCertificateCursor) Close() error {
+	return cursor.rows.Close()
+}
+
+// buildProgramCertificateConditions appends the filter values to args and
+// returns the matching " AND ..." fragment for the program certificate queries.
+func buildProgramCertificateConditions(filter domain.ProgramCertificateFilter, args *[]any) string {
+	var builder strings.Builder
+
+	if filter.Search != "" {
+		*args = append(*args, filter.Search)
+		query := searchTSQuery(len(*args))
+		builder.WriteString(fmt.Sprintf(" AND (p.search_vector @@ %[1]s OR co.search_vector @@ %[1]s OR c.search_vector @@ %[1]s OR pc.search_vector @@ %[1]s)", query))
+	}
+	if filter.ValidOnly {
+		builder.WriteString(" AND cs.code = 'valid' AND (pc.expiry_date IS NULL OR pc.expiry_date >= CURRENT_DATE)")
+	}
+	if filter.ActiveProductsOnly {
+		builder.WriteString(" AND p.is_active = TRUE")
+	}
+
+	appendCondition := func(condition string, value any) {
+		*args = append(*args, value)
+		builder.WriteString(fmt.Sprintf(condition, len(*args)))
+	}
+	if filter.StatusCode != "" {
+		appendCondition(" AND cs.code = $%d", filter.StatusCode)
+	}
+	if filter.CertificationID > 0 {
+		appendCondition(" AND c.id = $%d", filter.CertificationID)
+	}
+	if filter.BrandSlug != "" {
+		appendCondition(" AND b.slug = $%d", filter.BrandSlug)
+	}
+	if filter.BrandCategorySlug != "" {
+		appendCondition(" AND bc.slug = $%d", filter.BrandCategorySlug)
+	}
+	if filter.CompanySlug != "" {
+		appendCondition(" AND co.slug = $%d", filter.CompanySlug)
+	}
+	if filter.IssueFrom != nil {
+		appendCondition(" AND pc.issue_date >= $%d", *filter.IssueFrom)
+	}
+	if filter.IssueTo != nil {
+		appendCondition(" AND pc.issue_date <= $%d", *filter.IssueTo)
+	}
+	if filter.ExpiryFrom != nil {
+		appendCondition(" AND pc.expiry_date >= $%d", *filter.ExpiryFrom)
+	}
+	if filter.ExpiryTo != nil {
+		appendCondition(" AND pc.expiry_date <= $%d", *filter.ExpiryTo)
+	}
+	if filter.ExpiringWithinDays != nil {
+		appendCondition(" AND pc.expiry_date BETWEEN CURRENT_DATE AND CURRENT_DATE + $%d::int", *filter.ExpiringWithinDays)
+	}
+
+	return builder.String()
+}
+
+// programCertificateSortColumns maps the accepted sort fields to the columns
+// they order by.
+var programCertificateSortColumns = map[string]string{
+	domain.SortUpdatedAt:   "pc.updated_at",
+	domain.SortExpiryDate:  "pc.expiry_date",
+	domain.SortIssueDate:   "pc.issue_date",
+	domain.SortProductName: "p.name",
+	domain.SortCompany:     "co.name",
+}
+
+// programCertificateOrder returns the ORDER BY clause for the filter's sort.
+// Rows without a value sort last and ties are broken by ID in the same
+// direction.
+func programCertificateOrder(filter domain.ProgramCertificateFilter) (string, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = domain.SortUpdatedAt
+	}
+	column, ok := programCertificateSortColumns[sort]
+	if !ok {
+		return "", fmt.Errorf("unknown sort field %q", sort)
+	}
+	direction := "DESC"
+	if filter.SortAscending {
+		direction = "ASC"
+	}
+	if sort == domain.SortUpdatedAt {
+		return fmt.Sprintf("\nORDER BY %s %s, pc.id %s", column, direction, direction), nil
 	}
-	return certificates, total, nil
+	return fmt.Sprintf("\nORDER BY %s %s NULLS LAST, pc.id %s", column, direction, direction), nil
 }
 
 func (repository *ProductCertificationRepository) GetProgramCertificate(ctx context.Context, programCode, productSlug string, certificationID int64) (*domain.ProgramCertificate, error) {
@@ -148,6 +289,58 @@ func (repository *ProductCertificationRepository) GetProgramCertificate(ctx cont
 	return &record, nil
 }
 
+// GetProgramCertificateByNumber looks up a certificate by its number across all
+// programs. Matching is case-insensitive; the most recently updated row wins.
+func (repository *ProductCertificationRepository) GetProgramCertificateByNumber(ctx context.Context, certificateNo string) (*domain.ProgramCertificate, error) {
+	builder := strings.Builder{}
+	builder.WriteString(programCertificateSelect)
+	builder.WriteString("WHERE UPPER(pc.certificate_no) = UPPER($1)\nORDER BY pc.updated_at DESC, pc.id DESC\nLIMIT 1")
+
+	row := repository.DB.QueryRowContext(ctx, builder.String(), certificateNo)
+	record, err := scanProgramCertificate(row)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetProgramCertificateByID loads a certificate by its product_has_certification ID.
+func (repository *ProductCertificationRepository) GetProgramCertificateByID(ctx context.Context, certificateID int64) (*domain.ProgramCertificate, error) {
+	builder := strings.Builder{}
+	builder.WriteString(programCertificateSelect)
+	builder.WriteString("WHERE pc.id = $1")
+
+	row := repository.DB.QueryRowContext(ctx, builder.String(), certificateID)
+	record, err := scanProgramCertificate(row)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SetCertificateDocumentFile points a certificate at a stored document as
+// long as it still has the number and updated_at the document was rendered
+// from. It returns sql.ErrNoRows when the certificate was deleted or changed.
+func (repository *ProductCertificationRepository) SetCertificateDocumentFile(ctx context.Context, certificateID int64, certificateNo string, updatedAt time.Time, documentFile string) error {
+	const query = `
+		UPDATE public.product_has_certification
+		SET document_file = $2, updated_at = NOW()
+		WHERE id = $1 AND certificate_no = $3 AND updated_at = $4`
+
+	result, err := repository.DB.ExecContext(ctx, query, certificateID, documentFile, certificateNo, updatedAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (repository *ProductCertificationRepository) GetProductProgramCode(ctx context.Context, productSlug string) (string, error) {
 	const query = `
 		SELECT prog.code
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
 )
 
 type ProductRepository struct {
@@ -19,28 +20,50 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	return &ProductRepository{DB: db}
 }
 
-func (repository *ProductRepository) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, int, error) {
+// ListProducts returns a page of products, newest first. A cursor in the
+// filter replaces the offset, and the total is only counted unless SkipTotal
+// is set.
+func (repository *ProductRepository) ListProducts(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, pagination.Result, error) {
+	var result pagination.Result
 	whereClause, args := buildProductWhereClause(filter)
-	total, err := repository.countProducts(ctx, whereClause, args...)
-	if err != nil {
-		return nil, 0, err
+	if !filter.SkipTotal {
+		total, err := repository.countProducts(ctx, whereClause, args...)
+		if err != nil {
+			return nil, result, err
+		}
+		result.Total = &total
 	}
 
 	limit := filter.PageSize
 	offset := 0
-	if filter.Page > 0 && filter.PageSize > 0 {
+	if filter.Cursor == nil && filter.Page > 0 && filter.PageSize > 0 {
 		offset = (filter.Page - 1) * filter.PageSize
 	}
-	if limit > 0 && offset >= total {
-		return []domain.Product{}, total, nil
+	if limit > 0 && result.Total != nil && offset >= *result.Total {
+		return []domain.Product{}, result, nil
+	}
+
+	if filter.Cursor != nil {
+		condition := filter.Cursor.Condition("p.created_at", "p.id", &args)
+		if whereClause != "" {
+			whereClause += " AND "
+		}
+		whereClause += condition
 	}
 
-	products, err := repository.queryProducts(ctx, whereClause, "ORDER BY p.created_at DESC, p.id DESC", limit, offset, args...)
+	fetch := limit
+	if limit > 0 {
+		fetch = limit + 1
+	}
+	products, err := repository.queryProducts(ctx, whereClause, "ORDER BY p.created_at DESC, p.id DESC", fetch, offset, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, result, err
 	}
 
-	return products, total, nil
+	products, result.Next = pagination.Trim(products, limit, func(product domain.Product) pagination.Cursor {
+		return pagination.Cursor{Time: product.CreatedAt, ID: product.ID}
+	})
+	return products, result, nil
 }
 
 const (
@@ -140,9 +163,18 @@ func (repository *ProductRepository) mustGetProductBySlug(ctx context.Context, s
 }
 
 func (repository *ProductRepository) CreateProduct(ctx context.Context, payload domain.ProductPayload) (domain.Product, error) {
+	if _, err := InsertProduct(ctx, repository.DB, payload); err != nil {
+		return domain.Product{}, err
+	}
+	return repository.mustGetProductBySlug(ctx, payload.Slug)
+}
+
+// InsertProduct writes a new product with db, which may be a transaction,
+// and returns its ID.
+func InsertProduct(ctx context.Context, db DBTX, payload domain.ProductPayload) (int64, error) {
 	tshpJSON, imagesJSON, isActive, err := prepareProductJSON(payload)
 	if err != nil {
-		return domain.Product{}, err
+		return 0, err
 	}
 
 	const query = `
@@ -158,10 +190,10 @@ func (repository *ProductRepository) CreateProduct(ctx context.Context, payload
 			images,
 			is_active
 		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8::jsonb,$9::jsonb,$10)
-		RETURNING slug`
+		RETURNING id`
 
-	var slug string
-	err = repository.DB.QueryRowContext(
+	var id int64
+	err = db.QueryRowContext(
 		ctx,
 		query,
 		payload.CompanyID,
@@ -174,13 +206,8 @@ func (repository *ProductRepository) CreateProduct(ctx context.Context, payload
 		tshpJSON,
 		imagesJSON,
 		isActive,
-	).Scan(&slug)
-
-	if err != nil {
-		return domain.Product{}, err
-	}
-
-	return repository.mustGetProductBySlug(ctx, slug)
+	).Scan(&id)
+	return id, err
 }
 
 func (repository *ProductRepository) UpdateProduct(ctx context.Context, slug string, payload domain.ProductPayload) (domain.Product, error) {
@@ -255,8 +282,8 @@ func buildProductWhereClause(filter domain.ProductFilter) (string, []any) {
 		appendClause("bc.slug = $%d", filter.CategorySlug)
 	}
 	if filter.Search != "" {
-		condition := "(p.name ILIKE '%%' || $%[1]d || '%%' OR c.name ILIKE '%%' || $%[1]d || '%%')"
-		clauses = append(clauses, fmt.Sprintf(condition, pos))
+		query := searchTSQuery(pos)
+		clauses = append(clauses, fmt.Sprintf("(p.search_vector @@ %[1]s OR c.search_vector @@ %[1]s)", query))
 		args = append(args, filter.Search)
 		pos++
 	}
@@ -267,6 +294,71 @@ func buildProductWhereClause(filter domain.ProductFilter) (string, []any) {
 	return strings.Join(clauses, " AND "), args
 }
 
+// StreamProducts runs the list query without paging and returns a cursor
+// over its rows, in the same order as ListProducts.
+func (repository *ProductRepository) StreamProducts(ctx context.Context, filter domain.ProductFilter) (domain.ProductCursor, error) {
+	whereClause, args := buildProductWhereClause(filter)
+
+	var builder strings.Builder
+	builder.WriteString(baseProductSelect)
+	builder.WriteString(productExportColumns)
+	builder.WriteString(baseProductFrom)
+	if whereClause != "" {
+		builder.WriteString("WHERE ")
+		builder.WriteString(whereClause)
+		builder.WriteRune('\n')
+	}
+	builder.WriteString("ORDER BY p.created_at DESC, p.id DESC")
+
+	rows, err := repository.DB.QueryContext(ctx, builder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	return &productCursor{rows: rows}, nil
+}
+
+// productExportColumns extends baseProductSelect with the names exports
+// carry for each product.
+const productExportColumns = `,
+    pp.code,
+    b.name,
+    bc.name,
+    c.name
+`
+
+type productCursor struct {
+	rows *sql.Rows
+}
+
+func (cursor *productCursor) Next() bool {
+	return cursor.rows.Next()
+}
+
+func (cursor *productCursor) Product() (domain.ProductExport, error) {
+	var record domain.ProductExport
+	product, err := scanProduct(cursor.rows, &record.ProgramCode, &record.Brand, &record.BrandCategory, &record.Company)
+	if err != nil {
+		return domain.ProductExport{}, err
+	}
+	record.Product = product
+	return record, nil
+}
+
+func (cursor *productCursor) Err() error {
+	return cursor.rows.Err()
+}
+
+func (cursor *productCursor) Close() error {
+	return cursor.rows.Close()
+}
+
+// searchTSQuery parses the list search held in placeholder $pos with every
+// text search configuration of the search_vector columns, so the GIN indexes
+// serve both exact and stemmed words.
+func searchTSQuery(pos int) string {
+	return fmt.Sprintf("(websearch_to_tsquery('simple', $%[1]d) || websearch_to_tsquery('indonesian', $%[1]d) || websearch_to_tsquery('english', $%[1]d))", pos)
+}
+
 func (repository *ProductRepository) countProducts(ctx context.Context, whereClause string, args ...any) (int, error) {
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString(baseProductCountQuery)
@@ -282,7 +374,9 @@ func (repository *ProductRepository) countProducts(ctx context.Context, whereCla
 	return total, nil
 }
 
-func scanProduct(rows *sql.Rows) (domain.Product, error) {
+// scanProduct reads the baseProductSelect columns of a row, followed by any
+// extra columns into extra.
+func scanProduct(rows *sql.Rows, extra ...any) (domain.Product, error) {
 	var (
 		product   domain.Product
 		features  sql.NullString
@@ -293,7 +387,7 @@ func scanProduct(rows *sql.Rows) (domain.Product, error) {
 		updatedAt time.Time
 	)
 
-	if err := rows.Scan(
+	dest := []any{
 		&product.ID,
 		&product.Name,
 		&product.Slug,
@@ -304,7 +398,8 @@ func scanProduct(rows *sql.Rows) (domain.Product, error) {
 		&product.IsActive,
 		&createdAt,
 		&updatedAt,
-	); err != nil {
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
 		return domain.Product{}, err
 	}
 
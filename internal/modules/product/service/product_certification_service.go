@@ -2,13 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
 )
 
+// ErrStatusChangeNotAllowed is returned when a create or update tries to set
+// a status: certificates start in domain.InitialStatus and only change status
+// through the transition endpoints.
+var ErrStatusChangeNotAllowed = errors.New("status_change_not_allowed")
+
 type ProductCertificationRepository interface {
 	ListProductCertifications(ctx context.Context, productSlug string, filter domain.ProductCertificationFilter) ([]domain.ProductCertification, int, error)
-	CreateProductCertification(ctx context.Context, productSlug string, payload domain.ProductCertificationPayload) (domain.ProductCertification, error)
+	GetProductCertification(ctx context.Context, productSlug string, certificationID int64) (domain.ProductCertification, error)
+	CreateProductCertification(ctx context.Context, productSlug string, payload domain.ProductCertificationPayload, actorXID string) (domain.ProductCertification, error)
 	UpdateProductCertification(ctx context.Context, productSlug string, certificationID int64, payload domain.ProductCertificationPayload) (domain.ProductCertification, error)
 	DeleteProductCertification(ctx context.Context, productSlug string, certificationID int64) error
 }
@@ -42,12 +49,20 @@ func (service *ProductCertificationService) ListProductCertifications(ctx contex
 	}, nil
 }
 
+func (service *ProductCertificationService) GetProductCertification(ctx context.Context, productSlug string, certificationID int64) (domain.ProductCertification, error) {
+	return service.repository.GetProductCertification(ctx, productSlug, certificationID)
+}
+
 func (service *ProductCertificationService) CreateProductCertification(
 	ctx context.Context,
 	productSlug string,
 	payload domain.ProductCertificationPayload,
+	actorXID string,
 ) (domain.ProductCertification, error) {
-	return service.repository.CreateProductCertification(ctx, productSlug, payload)
+	if payload.StatusID != nil {
+		return domain.ProductCertification{}, ErrStatusChangeNotAllowed
+	}
+	return service.repository.CreateProductCertification(ctx, productSlug, payload, actorXID)
 }
 
 func (service *ProductCertificationService) UpdateProductCertification(
@@ -56,6 +71,18 @@ func (service *ProductCertificationService) UpdateProductCertification(
 	certificationID int64,
 	payload domain.ProductCertificationPayload,
 ) (domain.ProductCertification, error) {
+	// The update never writes the status, so a transition committed
+	// concurrently is kept. StatusID is only accepted when it restates the
+	// current status.
+	if payload.StatusID != nil {
+		current, err := service.repository.GetProductCertification(ctx, productSlug, certificationID)
+		if err != nil {
+			return domain.ProductCertification{}, err
+		}
+		if current.Status == nil || *payload.StatusID != current.Status.ID {
+			return domain.ProductCertification{}, ErrStatusChangeNotAllowed
+		}
+	}
 	return service.repository.UpdateProductCertification(ctx, productSlug, certificationID, payload)
 }
 
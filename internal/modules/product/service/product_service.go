@@ -4,10 +4,12 @@ import (
 	"context"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
 )
 
 type ProductRepository interface {
-	ListProducts(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, int, error)
+	ListProducts(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, pagination.Result, error)
+	StreamProducts(ctx context.Context, filter domain.ProductFilter) (domain.ProductCursor, error)
 	CreateProduct(ctx context.Context, payload domain.ProductPayload) (domain.Product, error)
 	GetProductBySlug(ctx context.Context, slug string) (*domain.Product, error)
 	UpdateProduct(ctx context.Context, slug string, payload domain.ProductPayload) (domain.Product, error)
@@ -23,25 +25,35 @@ func NewProductService(repo ProductRepository) *ProductService {
 }
 
 func (s *ProductService) ListProducts(ctx context.Context, filter domain.ProductFilter) (domain.ProductListResponse, error) {
-	if filter.Page <= 0 {
+	if filter.Cursor != nil {
+		filter.Page = 0
+	} else if filter.Page <= 0 {
 		filter.Page = 1
 	}
 	if filter.PageSize <= 0 {
 		filter.PageSize = 20
 	}
 
-	items, total, err := s.repo.ListProducts(ctx, filter)
+	items, page, err := s.repo.ListProducts(ctx, filter)
 	if err != nil {
 		return domain.ProductListResponse{}, err
 	}
 	return domain.ProductListResponse{
-		Items:    items,
-		Total:    total,
-		Page:     filter.Page,
-		PageSize: filter.PageSize,
+		Items:      items,
+		Total:      page.Total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		NextCursor: page.NextCursor(),
 	}, nil
 }
 
+// ExportProducts returns a cursor over every product matching the list
+// filters, ignoring paging. The caller must close it.
+func (s *ProductService) ExportProducts(ctx context.Context, filter domain.ProductFilter) (domain.ProductCursor, error) {
+	filter.Page, filter.PageSize, filter.Cursor = 0, 0, nil
+	return s.repo.StreamProducts(ctx, filter)
+}
+
 func (s *ProductService) CreateProduct(ctx context.Context, payload domain.ProductPayload) (domain.Product, error) {
 	return s.repo.CreateProduct(ctx, payload)
 }
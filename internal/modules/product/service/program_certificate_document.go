@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// GetByID loads a certificate by its ID regardless of program. The worker
+// uses it to render certificate documents.
+func (s *ProgramCertificateService) GetByID(ctx context.Context, certificateID int64) (domain.ProgramCertificate, error) {
+	record, err := s.repo.GetProgramCertificateByID(ctx, certificateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProgramCertificate{}, ErrCertificateNotFound
+		}
+		return domain.ProgramCertificate{}, err
+	}
+	return *record, nil
+}
+
+// AttachDocument points a certificate's document_file at a PDF rendered from
+// record. It returns ErrCertificateChanged when the certificate was deleted,
+// renewed or otherwise updated since record was loaded, so a stale PDF never
+// replaces the current one.
+func (s *ProgramCertificateService) AttachDocument(ctx context.Context, record domain.ProgramCertificate, documentFile string) error {
+	if err := s.repo.SetCertificateDocumentFile(ctx, record.ID, record.CertificateNo, record.UpdatedAt, documentFile); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCertificateChanged
+		}
+		return err
+	}
+	return nil
+}
+
+// QueueDocument asks the worker to render the certificate PDF when the
+// certificate is in a status that carries one. It reports whether a task was
+// queued.
+func (s *ProgramCertificateService) QueueDocument(ctx context.Context, record domain.ProgramCertificate) (bool, error) {
+	if record.Status == nil || !domain.CanIssueDocument(record.Status.Code) {
+		return false, nil
+	}
+	if err := s.documents.GenerateCertificateDocument(ctx, record.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RegenerateDocument queues a fresh PDF for a valid certificate on demand.
+func (s *ProgramCertificateService) RegenerateDocument(ctx context.Context, programCode, productSlug string, certificationID int64) (domain.ProgramCertificate, error) {
+	record, err := s.Get(ctx, programCode, productSlug, certificationID)
+	if err != nil {
+		return domain.ProgramCertificate{}, err
+	}
+	if record.Status == nil || !domain.CanIssueDocument(record.Status.Code) {
+		return domain.ProgramCertificate{}, ErrDocumentNotIssuable
+	}
+	if err := s.documents.GenerateCertificateDocument(ctx, record.ID); err != nil {
+		return domain.ProgramCertificate{}, err
+	}
+	return record, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// GetDetail returns a certificate with the chain of terms its renewals
+// replaced.
+func (s *ProgramCertificateService) GetDetail(ctx context.Context, programCode, productSlug string, certificationID int64) (domain.ProgramCertificateDetail, error) {
+	record, err := s.Get(ctx, programCode, productSlug, certificationID)
+	if err != nil {
+		return domain.ProgramCertificateDetail{}, err
+	}
+	renewals, err := s.repo.ListCertificateRenewals(ctx, record.ID)
+	if err != nil {
+		return domain.ProgramCertificateDetail{}, err
+	}
+	return domain.ProgramCertificateDetail{ProgramCertificate: record, Renewals: renewals}, nil
+}
+
+// Renew replaces the certificate number and validity window of a valid or
+// expired certificate, archiving the previous terms, and makes it valid.
+func (s *ProgramCertificateService) Renew(
+	ctx context.Context,
+	programCode, productSlug string,
+	certificationID int64,
+	payload domain.CertificateRenewalPayload,
+	actorXID string,
+) (domain.ProgramCertificateDetail, error) {
+	record, err := s.Get(ctx, programCode, productSlug, certificationID)
+	if err != nil {
+		return domain.ProgramCertificateDetail{}, err
+	}
+	from := ""
+	if record.Status != nil {
+		from = record.Status.Code
+	}
+	if !domain.CanRenew(from) {
+		return domain.ProgramCertificateDetail{}, ErrNotRenewable
+	}
+
+	payload.CertificateNo = strings.TrimSpace(payload.CertificateNo)
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	applied, err := s.repo.RenewCertificate(ctx, record.ID, from, payload, actorXID)
+	if err != nil {
+		return domain.ProgramCertificateDetail{}, err
+	}
+	if !applied {
+		return domain.ProgramCertificateDetail{}, ErrNotRenewable
+	}
+	return s.GetDetail(ctx, programCode, productSlug, certificationID)
+}
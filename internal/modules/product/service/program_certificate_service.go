@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/pagination"
 )
 
 var (
@@ -14,52 +17,109 @@ var (
 	ErrCertificationNotFound = errors.New("certification_not_found")
 	ErrProgramMismatch       = errors.New("program_mismatch")
 	ErrCertificateNotFound   = errors.New("program_certificate_not_found")
+	ErrUnknownTransition     = errors.New("unknown_status_transition")
+	ErrInvalidTransition     = errors.New("invalid_status_transition")
+	ErrNotRenewable          = errors.New("certificate_not_renewable")
+	ErrDocumentNotIssuable   = errors.New("certificate_document_not_issuable")
+	ErrCertificateChanged    = errors.New("certificate_changed")
+	ErrCursorNotSupported    = errors.New("cursor_not_supported_for_sort")
 )
 
 type ProgramCertificateRepository interface {
 	GetProductProgramCode(ctx context.Context, productSlug string) (string, error)
 	GetCertificationProgramCode(ctx context.Context, certificationID int64) (string, error)
-	ListProgramCertificates(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) ([]domain.ProgramCertificate, int, error)
+	ListProgramCertificates(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) ([]domain.ProgramCertificate, pagination.Result, error)
+	StreamProgramCertificates(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) (domain.ProgramCertificateCursor, error)
 	GetProgramCertificate(ctx context.Context, programCode, productSlug string, certificationID int64) (*domain.ProgramCertificate, error)
+	GetProgramCertificateByNumber(ctx context.Context, certificateNo string) (*domain.ProgramCertificate, error)
+	ExpireCertificates(ctx context.Context, runDate time.Time) (int64, error)
+	ListDueExpiryReminders(ctx context.Context, runDate time.Time, daysBefore []int) ([]domain.ExpiryReminder, error)
+	ClaimExpiryReminder(ctx context.Context, reminder domain.ExpiryReminder) (bool, error)
+	ReleaseExpiryReminder(ctx context.Context, reminder domain.ExpiryReminder) error
+	TransitionCertificateStatus(ctx context.Context, certificateID int64, from, to, reason, actorXID string) (bool, error)
+	ListCertificateStatusHistory(ctx context.Context, certificateID int64) ([]domain.CertificateStatusChange, error)
+	RenewCertificate(ctx context.Context, certificateID int64, from string, payload domain.CertificateRenewalPayload, actorXID string) (bool, error)
+	ListCertificateRenewals(ctx context.Context, certificateID int64) ([]domain.CertificateRenewal, error)
+	FindRenewalByNumber(ctx context.Context, certificateNo string) (int64, domain.CertificateRenewal, error)
+	GetProgramCertificateByID(ctx context.Context, certificateID int64) (*domain.ProgramCertificate, error)
+	SetCertificateDocumentFile(ctx context.Context, certificateID int64, certificateNo string, updatedAt time.Time, documentFile string) error
+}
+
+// DocumentDispatcher queues certificate PDF rendering for the worker;
+// queue.Dispatcher implements it.
+type DocumentDispatcher interface {
+	GenerateCertificateDocument(ctx context.Context, certificateID int64) error
 }
 
 type ProgramCertificateService struct {
 	repo               ProgramCertificateRepository
 	productCertService *ProductCertificationService
+	documents          DocumentDispatcher
 }
 
 func NewProgramCertificateService(
 	repo ProgramCertificateRepository,
 	productCertService *ProductCertificationService,
+	documents DocumentDispatcher,
 ) *ProgramCertificateService {
 	return &ProgramCertificateService{
 		repo:               repo,
 		productCertService: productCertService,
+		documents:          documents,
 	}
 }
 
 func (s *ProgramCertificateService) List(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) (domain.ProgramCertificateListResponse, error) {
-	if filter.Page <= 0 {
+	if filter.Cursor != nil {
+		if !filter.Keyset() {
+			return domain.ProgramCertificateListResponse{}, ErrCursorNotSupported
+		}
+		filter.Page = 0
+	} else if filter.Page <= 0 {
 		filter.Page = 1
 	}
 	if filter.PageSize <= 0 {
 		filter.PageSize = 20
 	}
 
-	items, total, err := s.repo.ListProgramCertificates(ctx, programCode, filter)
+	items, page, err := s.repo.ListProgramCertificates(ctx, programCode, filter)
 	if err != nil {
 		return domain.ProgramCertificateListResponse{}, err
 	}
 
 	return domain.ProgramCertificateListResponse{
-		Items:    items,
-		Total:    total,
-		Page:     filter.Page,
-		PageSize: filter.PageSize,
+		Items:      items,
+		Total:      page.Total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		NextCursor: page.NextCursor(),
 	}, nil
 }
 
-func (s *ProgramCertificateService) Create(ctx context.Context, programCode string, payload domain.ProgramCertificatePayload) (domain.ProgramCertificate, error) {
+// Export returns a cursor over every certificate matching filter, ignoring
+// its paging. The caller must close it.
+func (s *ProgramCertificateService) Export(ctx context.Context, programCode string, filter domain.ProgramCertificateFilter) (domain.ProgramCertificateCursor, error) {
+	filter.Page, filter.PageSize, filter.Cursor = 0, 0, nil
+	return s.repo.StreamProgramCertificates(ctx, programCode, filter)
+}
+
+func (s *ProgramCertificateService) Get(ctx context.Context, programCode, productSlug string, certificationID int64) (domain.ProgramCertificate, error) {
+	record, err := s.repo.GetProgramCertificate(ctx, programCode, productSlug, certificationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProgramCertificate{}, ErrCertificateNotFound
+		}
+		return domain.ProgramCertificate{}, err
+	}
+	if record == nil {
+		return domain.ProgramCertificate{}, ErrCertificateNotFound
+	}
+	return *record, nil
+}
+
+// Create adds a certificate in domain.InitialStatus; see
+// ProductCertificationService.CreateProductCertification.
+func (s *ProgramCertificateService) Create(ctx context.Context, programCode string, payload domain.ProgramCertificatePayload, actorXID string) (domain.ProgramCertificate, error) {
 	if err := s.ensureProgramConsistency(ctx, programCode, payload.ProductSlug, payload.CertificationID); err != nil {
 		return domain.ProgramCertificate{}, err
 	}
@@ -74,7 +134,7 @@ func (s *ProgramCertificateService) Create(ctx context.Context, programCode stri
 		Meta:            payload.Meta,
 	}
 
-	if _, err := s.productCertService.CreateProductCertification(ctx, payload.ProductSlug, certPayload); err != nil {
+	if _, err := s.productCertService.CreateProductCertification(ctx, payload.ProductSlug, certPayload, actorXID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.ProgramCertificate{}, ErrCertificateNotFound
 		}
@@ -138,6 +198,40 @@ func (s *ProgramCertificateService) Delete(ctx context.Context, programCode, pro
 	return nil
 }
 
+// Verify resolves a certificate number into a public verification verdict.
+// A number that a renewal replaced is reported as superseded by the
+// certificate's current number, so verifiers can tell it from a forgery.
+func (s *ProgramCertificateService) Verify(ctx context.Context, certificateNo string) (domain.CertificateVerification, error) {
+	certificateNo = strings.TrimSpace(certificateNo)
+	if certificateNo == "" {
+		return domain.CertificateVerification{}, ErrCertificateNotFound
+	}
+
+	record, err := s.repo.GetProgramCertificateByNumber(ctx, certificateNo)
+	if err == nil {
+		return domain.NewCertificateVerification(*record, time.Now().UTC()), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return domain.CertificateVerification{}, err
+	}
+
+	certificateID, renewal, err := s.repo.FindRenewalByNumber(ctx, certificateNo)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CertificateVerification{}, ErrCertificateNotFound
+		}
+		return domain.CertificateVerification{}, err
+	}
+	current, err := s.repo.GetProgramCertificateByID(ctx, certificateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CertificateVerification{}, ErrCertificateNotFound
+		}
+		return domain.CertificateVerification{}, err
+	}
+	return domain.NewSupersededVerification(renewal, *current, time.Now().UTC()), nil
+}
+
 func (s *ProgramCertificateService) ensureProgramConsistency(ctx context.Context, programCode, productSlug string, certificationID int64) error {
 	productProgram, err := s.repo.GetProductProgramCode(ctx, productSlug)
 	if err != nil {
@@ -167,3 +261,24 @@ func (s *ProgramCertificateService) ensureProgramConsistency(ctx context.Context
 
 	return nil
 }
+
+// ExpireDue marks every valid certificate that expired before runDate as
+// expired and returns how many rows changed.
+func (s *ProgramCertificateService) ExpireDue(ctx context.Context, runDate time.Time) (int64, error) {
+	return s.repo.ExpireCertificates(ctx, runDate)
+}
+
+func (s *ProgramCertificateService) DueExpiryReminders(ctx context.Context, runDate time.Time, daysBefore []int) ([]domain.ExpiryReminder, error) {
+	if len(daysBefore) == 0 {
+		return nil, nil
+	}
+	return s.repo.ListDueExpiryReminders(ctx, runDate, daysBefore)
+}
+
+func (s *ProgramCertificateService) ClaimExpiryReminder(ctx context.Context, reminder domain.ExpiryReminder) (bool, error) {
+	return s.repo.ClaimExpiryReminder(ctx, reminder)
+}
+
+func (s *ProgramCertificateService) ReleaseExpiryReminder(ctx context.Context, reminder domain.ExpiryReminder) error {
+	return s.repo.ReleaseExpiryReminder(ctx, reminder)
+}
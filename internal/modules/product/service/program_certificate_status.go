@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+)
+
+// Transition applies one of domain.StatusTransitions to a certificate and
+// records the reason and actor in its status history.
+func (s *ProgramCertificateService) Transition(
+	ctx context.Context,
+	programCode, productSlug string,
+	certificationID int64,
+	action, reason, actorXID string,
+) (domain.ProgramCertificate, error) {
+	transition, ok := domain.StatusTransitions[action]
+	if !ok {
+		return domain.ProgramCertificate{}, ErrUnknownTransition
+	}
+
+	record, err := s.Get(ctx, programCode, productSlug, certificationID)
+	if err != nil {
+		return domain.ProgramCertificate{}, err
+	}
+	from := ""
+	if record.Status != nil {
+		from = record.Status.Code
+	}
+	if !transition.Allows(from) {
+		return domain.ProgramCertificate{}, ErrInvalidTransition
+	}
+
+	applied, err := s.repo.TransitionCertificateStatus(ctx, record.ID, from, transition.To, strings.TrimSpace(reason), actorXID)
+	if err != nil {
+		return domain.ProgramCertificate{}, err
+	}
+	if !applied {
+		// The status changed between the read and the update.
+		return domain.ProgramCertificate{}, ErrInvalidTransition
+	}
+	return s.Get(ctx, programCode, productSlug, certificationID)
+}
+
+func (s *ProgramCertificateService) StatusHistory(ctx context.Context, programCode, productSlug string, certificationID int64) ([]domain.CertificateStatusChange, error) {
+	record, err := s.Get(ctx, programCode, productSlug, certificationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListCertificateStatusHistory(ctx, record.ID)
+}
@@ -15,7 +15,7 @@ type Module struct {
 	CertificationRepo    service.ProductCertificationRepository
 }
 
-func Provide(db *sql.DB) *Module {
+func Provide(db *sql.DB, documents service.DocumentDispatcher) *Module {
 	productRepo := postgres.NewProductRepository(db)
 	certRepo := postgres.NewProductCertificationRepository(db)
 	certService := service.NewProductCertificationService(certRepo)
@@ -25,6 +25,6 @@ func Provide(db *sql.DB) *Module {
 		CertificationRepo:    certRepo,
 		Service:              service.NewProductService(productRepo),
 		CertificationService: certService,
-		ProgramCertService:   service.NewProgramCertificateService(certRepo, certService),
+		ProgramCertService:   service.NewProgramCertificateService(certRepo, certService, documents),
 	}
 }
@@ -177,3 +177,34 @@ func (r *RBACRepository) ListUserPermissions(ctx context.Context, userXID string
 	}
 	return perms, nil
 }
+
+func (r *RBACRepository) ListRoleMemberEmails(ctx context.Context, roleName string) ([]string, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT DISTINCT u.email
+		FROM users u
+		JOIN user_roles ur ON ur.user_id = u.id
+		JOIN roles r ON r.id = ur.role_id
+		WHERE r.name = $1
+		  AND u.is_active = TRUE
+		  AND u.deleted_at IS NULL
+		  AND r.deleted_at IS NULL
+		ORDER BY u.email
+	`, roleName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
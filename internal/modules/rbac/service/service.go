@@ -16,6 +16,7 @@ type Repository interface {
 	UserHasPermissionByXID(ctx context.Context, userXID, permKey string) (bool, error)
 	ListUserRoles(ctx context.Context, userXID string) ([]string, error)
 	ListUserPermissions(ctx context.Context, userXID string) ([]string, error)
+	ListRoleMemberEmails(ctx context.Context, roleName string) ([]string, error)
 }
 
 type Service struct {
@@ -61,3 +62,8 @@ func (s *Service) ListUserRoles(ctx context.Context, userXID string) ([]string,
 func (s *Service) ListUserPermissions(ctx context.Context, userXID string) ([]string, error) {
 	return s.repo.ListUserPermissions(ctx, userXID)
 }
+
+// ListRoleMemberEmails returns the addresses of active users holding the role.
+func (s *Service) ListRoleMemberEmails(ctx context.Context, roleName string) ([]string, error) {
+	return s.repo.ListRoleMemberEmails(ctx, roleName)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "errors"
+
+const (
+	TypeProduct     = "products"
+	TypeBrand       = "brands"
+	TypeCompany     = "companies"
+	TypeCertificate = "certificates"
+)
+
+var (
+	ErrEmptyQuery   = errors.New("empty_search_query")
+	ErrQueryTooLong = errors.New("search_query_too_long")
+	ErrInvalidType  = errors.New("invalid_search_type")
+)
+
+// Types lists the searchable entity types in response order.
+var Types = []string{TypeProduct, TypeBrand, TypeCompany, TypeCertificate}
+
+// Hit is one ranked match. Snippet is plain text with the matched terms
+// wrapped in <mark> tags; it is not HTML-escaped.
+type Hit struct {
+	ID      int64   `json:"id"`
+	Title   string  `json:"title"`
+	Slug    string  `json:"slug,omitempty"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+	// Program is the program code of a product or certificate.
+	Program string `json:"program,omitempty"`
+	// ProductSlug and Certification describe the product a certificate
+	// belongs to.
+	ProductSlug   string `json:"product_slug,omitempty"`
+	Certification string `json:"certification,omitempty"`
+}
+
+type SearchFilter struct {
+	Query string
+	Types []string
+	// Limit caps the hits returned per type.
+	Limit int
+}
+
+// SearchResponse groups hits by entity type. Types that were not searched
+// are omitted.
+type SearchResponse struct {
+	Query        string `json:"query"`
+	Products     []Hit  `json:"products,omitempty"`
+	Brands       []Hit  `json:"brands,omitempty"`
+	Companies    []Hit  `json:"companies,omitempty"`
+	Certificates []Hit  `json:"certificates,omitempty"`
+}
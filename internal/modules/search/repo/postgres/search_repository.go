@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/search/domain"
+)
+
+type SearchRepository struct {
+	DB *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{DB: db}
+}
+
+// searchQuery ORs the query parsed with each text search configuration used
+// by the search_vector columns, so unstemmed and stemmed terms both match.
+const searchQuery = `
+WITH q AS (
+	SELECT websearch_to_tsquery('simple', $1)
+	    || websearch_to_tsquery('indonesian', $1)
+	    || websearch_to_tsquery('english', $1) AS query
+)`
+
+// headlineOptions marks matches for clients without letting long texts
+// produce long snippets.
+const headlineOptions = `'StartSel=<mark>, StopSel=</mark>, MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "'`
+
+var searchQueries = map[string]string{
+	domain.TypeProduct: searchQuery + `
+SELECT p.id, p.name, p.slug, prog.code, '', '',
+       ts_rank(p.search_vector, q.query) AS rank,
+       ts_headline('english', concat_ws(' ', p.name, p.features, p.reason), q.query, ` + headlineOptions + `)
+FROM public.products p
+JOIN public.lkp_product_program prog ON prog.id = p.program_id
+CROSS JOIN q
+WHERE p.search_vector @@ q.query
+ORDER BY rank DESC, p.id DESC
+LIMIT $2`,
+
+	domain.TypeBrand: searchQuery + `
+SELECT b.id, b.name, b.slug, '', '', '',
+       ts_rank(b.search_vector, q.query) AS rank,
+       ts_headline('english', b.name, q.query, ` + headlineOptions + `)
+FROM public.brands b
+CROSS JOIN q
+WHERE b.search_vector @@ q.query
+ORDER BY rank DESC, b.id DESC
+LIMIT $2`,
+
+	domain.TypeCompany: searchQuery + `
+SELECT co.id, co.name, co.slug, '', '', '',
+       ts_rank(co.search_vector, q.query) AS rank,
+       ts_headline('english', concat_ws(' ', co.name, co.address), q.query, ` + headlineOptions + `)
+FROM public.companies co
+CROSS JOIN q
+WHERE co.search_vector @@ q.query
+ORDER BY rank DESC, co.id DESC
+LIMIT $2`,
+
+	domain.TypeCertificate: searchQuery + `
+SELECT pc.id, pc.certificate_no, '', prog.code, p.slug, c.name,
+       ts_rank(pc.search_vector, q.query) AS rank,
+       ts_headline('simple', pc.certificate_no, q.query, ` + headlineOptions + `)
+FROM public.product_has_certification pc
+JOIN public.products p ON p.id = pc.product_id
+JOIN public.certifications c ON c.id = pc.certification_id
+JOIN public.lkp_product_program prog ON prog.id = c.program_id
+CROSS JOIN q
+WHERE pc.search_vector @@ q.query
+ORDER BY rank DESC, pc.id DESC
+LIMIT $2`,
+}
+
+// Search returns the best ranked hits of one entity type.
+func (r *SearchRepository) Search(ctx context.Context, entityType, query string, limit int) ([]domain.Hit, error) {
+	statement, ok := searchQueries[entityType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidType, entityType)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, statement, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := make([]domain.Hit, 0)
+	for rows.Next() {
+		var (
+			hit   domain.Hit
+			title sql.NullString
+		)
+		if err := rows.Scan(
+			&hit.ID,
+			&title,
+			&hit.Slug,
+			&hit.Program,
+			&hit.ProductSlug,
+			&hit.Certification,
+			&hit.Rank,
+			&hit.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		hit.Title = title.String
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/search/domain"
+)
+
+const (
+	// defaultLimit is the number of hits returned per type when none is given.
+	defaultLimit = 5
+	// MaxLimit caps the hits returned per type.
+	MaxLimit = 50
+	// MaxQueryLength is the longest query accepted, in characters.
+	MaxQueryLength = 200
+)
+
+type Repository interface {
+	Search(ctx context.Context, entityType, query string, limit int) ([]domain.Hit, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Search runs the query against each requested type, or all types when none
+// are given, and groups the ranked hits by type.
+func (s *Service) Search(ctx context.Context, filter domain.SearchFilter) (domain.SearchResponse, error) {
+	query := strings.TrimSpace(filter.Query)
+	if query == "" {
+		return domain.SearchResponse{}, domain.ErrEmptyQuery
+	}
+	if utf8.RuneCountInString(query) > MaxQueryLength {
+		return domain.SearchResponse{}, domain.ErrQueryTooLong
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+	filter.Limit = min(filter.Limit, MaxLimit)
+
+	types := filter.Types
+	if len(types) == 0 {
+		types = domain.Types
+	}
+	for _, entityType := range types {
+		if !slices.Contains(domain.Types, entityType) {
+			return domain.SearchResponse{}, domain.ErrInvalidType
+		}
+	}
+
+	result := domain.SearchResponse{Query: query}
+	for _, entityType := range domain.Types {
+		if !slices.Contains(types, entityType) {
+			continue
+		}
+		hits, err := s.repo.Search(ctx, entityType, query, filter.Limit)
+		if err != nil {
+			return domain.SearchResponse{}, err
+		}
+		switch entityType {
+		case domain.TypeProduct:
+			result.Products = hits
+		case domain.TypeBrand:
+			result.Brands = hits
+		case domain.TypeCompany:
+			result.Companies = hits
+		case domain.TypeCertificate:
+			result.Certificates = hits
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,21 @@
+package search
+
+import (
+	"database/sql"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/search/repo/postgres"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/search/service"
+)
+
+type Module struct {
+	Repository *postgres.SearchRepository
+	Service    *service.Service
+}
+
+func Provide(db *sql.DB) *Module {
+	repo := postgres.NewSearchRepository(db)
+	return &Module{
+		Repository: repo,
+		Service:    service.NewService(repo),
+	}
+}
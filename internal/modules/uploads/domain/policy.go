@@ -0,0 +1,80 @@
+package domain
+
+import "fmt"
+
+const (
+	CategoryImages    = "images"
+	CategoryVideos    = "videos"
+	CategoryDocuments = "documents"
+	CategoryArchives  = "archives"
+
+	RuleCategory  = "category"
+	RuleExtension = "extension"
+	RuleMaxSize   = "max_size"
+	RuleMaxFiles  = "max_files"
+	RuleMaxWidth  = "max_width"
+	RuleMaxHeight = "max_height"
+	// RuleImageDecode rejects images whose dimensions cannot be read under a
+	// policy that limits them.
+	RuleImageDecode = "image_decode"
+)
+
+// ModuleConfig places the uploads of a module under Path, relative to the
+// storage base path, and limits them with Policy.
+type ModuleConfig struct {
+	Path   string
+	Policy Policy
+	// KeepUnreferenced keeps the module's uploads out of the orphan cleanup,
+	// for modules whose files no stored column points at.
+	KeepUnreferenced bool
+}
+
+// Policy limits the files accepted for a module. Empty lists and zero limits
+// do not restrict anything. Files are only accepted when their type is
+// recognised at all, so Categories and Extensions narrow the built-in types
+// rather than add to them.
+type Policy struct {
+	Categories []string
+	// Extensions are lower-case and include the dot, e.g. ".pdf".
+	Extensions []string
+	// MaxSize is in bytes.
+	MaxSize  int64
+	MaxFiles int
+	// MaxWidth and MaxHeight bound images in pixels. When either is set,
+	// images whose size cannot be read are rejected.
+	MaxWidth  int
+	MaxHeight int
+	// Private files are only served to users allowed to read private files.
+	Private bool
+}
+
+// DefaultPolicy applies to modules without their own configuration.
+var DefaultPolicy = Policy{
+	MaxSize:  100 << 20,
+	MaxFiles: 10,
+}
+
+// PolicyError reports the policy rule a file broke. File is the original
+// filename and is empty for request-wide rules such as RuleMaxFiles.
+type PolicyError struct {
+	Module string
+	Rule   string
+	File   string
+	Limit  any
+	Value  any
+}
+
+func (e *PolicyError) Error() string {
+	subject := "upload"
+	if e.File != "" {
+		subject = fmt.Sprintf("file %q", e.File)
+	}
+	switch e.Rule {
+	case RuleCategory, RuleExtension:
+		return fmt.Sprintf("%s: %s %v is not allowed, allowed: %v", subject, e.Rule, e.Value, e.Limit)
+	case RuleImageDecode:
+		return fmt.Sprintf("%s: image dimensions cannot be read", subject)
+	default:
+		return fmt.Sprintf("%s: %v exceeds the %s of %v", subject, e.Value, e.Rule, e.Limit)
+	}
+}
@@ -1,11 +1,23 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrEmptyFile       = errors.New("file is empty")
 	ErrUnsupportedType = errors.New("file type is not allowed")
 	ErrNoFilesProvided = errors.New("no files provided")
+	ErrObjectNotFound  = errors.New("object not found")
+	ErrFileTooLarge    = errors.New("file is too large")
+	ErrUploadNotFound  = errors.New("upload not found")
+	// ErrUploadMismatch means a directly uploaded object differs from the
+	// size or type it was presigned for.
+	ErrUploadMismatch = errors.New("uploaded file does not match the request")
+	// ErrImageTooLarge means an image has too many pixels to be decoded for
+	// derivatives.
+	ErrImageTooLarge = errors.New("image is too large")
 )
 
 type UploadResult struct {
@@ -15,4 +27,101 @@ type UploadResult struct {
 	MimeType         string `json:"mime_type"`
 	Size             int64  `json:"size"`
 	Category         string `json:"category"`
+	// Checksum is the hex-encoded SHA-256 of the stored content. It is empty
+	// for direct uploads, which the API never reads in full.
+	Checksum string `json:"checksum,omitempty"`
+	// Derivatives lists the resized copies queued for an image. They are
+	// stored in Directory once the worker has generated them.
+	Derivatives []Derivative `json:"derivatives,omitempty"`
+}
+
+// Derivative is a resized copy of an uploaded image, named after the
+// original: <name>_<width>.<ext>.
+type Derivative struct {
+	Width    int    `json:"width"`
+	Format   string `json:"format"`
+	Filename string `json:"filename"`
+}
+
+// DerivativeConfig selects the derivatives generated for uploaded images.
+// Every width is produced in the original's format (JPEG for JPEG sources,
+// PNG otherwise) and, when WebP is set, also as WebP. Images are never
+// scaled up, so a width above the original keeps the original size.
+type DerivativeConfig struct {
+	Widths []int
+	WebP   bool
+}
+
+// NewUpload is the record written for each stored object.
+type NewUpload struct {
+	ObjectKey        string
+	Module           string
+	Category         string
+	OriginalFilename string
+	MimeType         string
+	Size             int64
+	Checksum         string
+	// UploadedBy is the uploader's xid, empty for files generated by the
+	// worker.
+	UploadedBy string
+}
+
+// Upload is a recorded object together with the keys of its derivatives.
+type Upload struct {
+	ID          int64
+	ObjectKey   string
+	Derivatives []string
+	CreatedAt   time.Time
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// DirectUploadConfig limits uploads sent straight to storage with a
+// presigned URL. Expiry is how long the URL stays valid.
+type DirectUploadConfig struct {
+	MaxSize int64
+	Expiry  time.Duration
+}
+
+// DirectUploadRequest describes a file the client is about to upload with a
+// presigned URL.
+type DirectUploadRequest struct {
+	Module      string `json:"module"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// DirectUpload is an issued presigned POST. The client sends a
+// multipart/form-data request to URL with every entry of Fields followed by
+// the file in a field named "file", and then completes the upload by ID.
+// Storage rejects a file of another Content-Type or one larger than the
+// module allows.
+type DirectUpload struct {
+	ID        int64             `json:"id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Fields    map[string]string `json:"fields"`
+	Directory string            `json:"directory"`
+	Filename  string            `json:"filename"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// PendingUpload is kept for a direct upload until it is completed.
+type PendingUpload struct {
+	ID               int64
+	ObjectKey        string
+	Module           string
+	Category         string
+	OriginalFilename string
+	MimeType         string
+	Size             int64
+	UploadedBy       string
+	ExpiresAt        time.Time
 }
@@ -0,0 +1,246 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+// Repository stores objects as files under root, one directory per bucket.
+// It is meant for local development: presigned URLs point at baseURL, where
+// the API serves them with a storage handler, and are signed with secret.
+type Repository struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+func New(root, baseURL string, secret []byte) *Repository {
+	return &Repository{root: root, baseURL: strings.TrimSuffix(baseURL, "/"), secret: secret}
+}
+
+// EnsureBucket creates the bucket directory.
+func (r *Repository) EnsureBucket(ctx context.Context, bucket, region string) error {
+	dir, err := r.FilePath(bucket, "")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create bucket: %w", err)
+	}
+	return nil
+}
+
+// PutObject writes the object to a temporary file first and renames it into
+// place, so readers never see a partial file. Like MinIO, it fails when the
+// reader ends before size bytes.
+func (r *Repository) PutObject(ctx context.Context, bucket, objectName string, reader io.ReadSeeker, size int64, contentType string) error {
+	target, err := r.FilePath(bucket, objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(reader, size))
+	if err == nil && written < size {
+		err = fmt.Errorf("object %s: read %d of %d bytes: %w", objectName, written, size, io.ErrUnexpectedEOF)
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (r *Repository) GetObject(ctx context.Context, bucket, objectName string) (io.ReadCloser, error) {
+	return r.GetObjectRange(ctx, bucket, objectName, 0, 0)
+}
+
+// GetObjectRange opens length bytes of an object starting at offset. A
+// length of 0 reads to the end.
+func (r *Repository) GetObjectRange(ctx context.Context, bucket, objectName string, offset, length int64) (io.ReadCloser, error) {
+	name, err := r.FilePath(bucket, objectName)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	if length <= 0 {
+		return file, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, length), file}, nil
+}
+
+// StatObject describes a stored file. The content type is derived from the
+// file extension and the ETag from its size and modification time.
+func (r *Repository) StatObject(ctx context.Context, bucket, objectName string) (domain.ObjectInfo, error) {
+	name, err := r.FilePath(bucket, objectName)
+	if err != nil {
+		return domain.ObjectInfo{}, err
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return domain.ObjectInfo{}, mapError(err)
+	}
+	if info.IsDir() {
+		return domain.ObjectInfo{}, domain.ErrObjectNotFound
+	}
+	return domain.ObjectInfo{
+		Size:         info.Size(),
+		ContentType:  mime.TypeByExtension(path.Ext(objectName)),
+		ETag:         fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size()),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// RemoveObject deletes a stored file. Removing a missing file is not an
+// error.
+func (r *Repository) RemoveObject(ctx context.Context, bucket, objectName string) error {
+	name, err := r.FilePath(bucket, objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PostPolicy is the upload allowed by a form from PresignPost.
+type PostPolicy struct {
+	Key         string
+	ContentType string
+	MinSize     int64
+	MaxSize     int64
+}
+
+// PresignPost returns the URL and signed form fields of a POST that uploads
+// objectName with the given Content-Type and a size between minSize and
+// maxSize bytes.
+func (r *Repository) PresignPost(ctx context.Context, bucket, objectName, contentType string, minSize, maxSize int64, expiry time.Duration) (string, map[string]string, error) {
+	if _, err := r.FilePath(bucket, objectName); err != nil {
+		return "", nil, err
+	}
+	fields := map[string]string{
+		"key":          objectName,
+		"Content-Type": contentType,
+		"min_size":     strconv.FormatInt(minSize, 10),
+		"max_size":     strconv.FormatInt(maxSize, 10),
+		"expires":      strconv.FormatInt(time.Now().Add(expiry).Unix(), 10),
+	}
+	fields["signature"] = r.sign("POST", bucket, fields["key"], fields["Content-Type"], fields["min_size"], fields["max_size"], fields["expires"])
+	return r.baseURL + "/" + bucket, fields, nil
+}
+
+// PresignGet returns a signed URL for downloading objectName.
+func (r *Repository) PresignGet(ctx context.Context, bucket, objectName string, expiry time.Duration) (string, error) {
+	if _, err := r.FilePath(bucket, objectName); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", r.sign("GET", bucket, objectName, expires))
+	return r.baseURL + "/" + bucket + "/" + objectName + "?" + query.Encode(), nil
+}
+
+// VerifySignature checks a URL issued by PresignGet.
+func (r *Repository) VerifySignature(bucket, objectName, expires, signature string) bool {
+	if expired(expires) {
+		return false
+	}
+	return hmac.Equal([]byte(r.sign("GET", bucket, objectName, expires)), []byte(signature))
+}
+
+// VerifyPost checks the fields of a form from PresignPost and returns the
+// upload they allow.
+func (r *Repository) VerifyPost(bucket string, fields map[string]string) (PostPolicy, bool) {
+	if expired(fields["expires"]) {
+		return PostPolicy{}, false
+	}
+	expected := r.sign("POST", bucket, fields["key"], fields["Content-Type"], fields["min_size"], fields["max_size"], fields["expires"])
+	if !hmac.Equal([]byte(expected), []byte(fields["signature"])) {
+		return PostPolicy{}, false
+	}
+	minSize, err := strconv.ParseInt(fields["min_size"], 10, 64)
+	if err != nil {
+		return PostPolicy{}, false
+	}
+	maxSize, err := strconv.ParseInt(fields["max_size"], 10, 64)
+	if err != nil {
+		return PostPolicy{}, false
+	}
+	return PostPolicy{Key: fields["key"], ContentType: fields["Content-Type"], MinSize: minSize, MaxSize: maxSize}, true
+}
+
+// FilePath maps an object to its file. Keys that would leave the bucket
+// directory are reported as domain.ErrObjectNotFound.
+func (r *Repository) FilePath(bucket, objectName string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, `/\`) || bucket == "." || bucket == ".." {
+		return "", domain.ErrObjectNotFound
+	}
+	if objectName != "" && (path.Clean(objectName) != objectName || strings.HasPrefix(objectName, "/") ||
+		objectName == ".." || strings.HasPrefix(objectName, "../")) {
+		return "", domain.ErrObjectNotFound
+	}
+	return filepath.Join(r.root, bucket, filepath.FromSlash(objectName)), nil
+}
+
+// sign returns the hex-encoded HMAC of parts. Each part is prefixed with its
+// length, so moving text from one part to the next changes the signature.
+func (r *Repository) sign(parts ...string) string {
+	mac := hmac.New(sha256.New, r.secret)
+	for _, part := range parts {
+		fmt.Fprintf(mac, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func expired(expires string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	return err != nil || time.Now().Unix() > unix
+}
+
+func mapError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return domain.ErrObjectNotFound
+	}
+	return err
+}
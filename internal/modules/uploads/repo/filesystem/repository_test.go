@@ -0,0 +1,212 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+func presignedQuery(t *testing.T, rawURL string) (string, string) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parse %q: %v", rawURL, err)
+	}
+	return u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestVerifySignature(t *testing.T) {
+	repo := New(t.TempDir(), "http://localhost/storage", []byte("test-secret"))
+
+	getURL, err := repo.PresignGet(context.Background(), "uploads", "documents/a.pdf", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, signature := presignedQuery(t, getURL)
+	if !repo.VerifySignature("uploads", "documents/a.pdf", expires, signature) {
+		t.Fatal("valid GET signature rejected")
+	}
+
+	tests := []struct {
+		name                               string
+		bucket, object, expires, signature string
+	}{
+		{"other bucket", "other", "documents/a.pdf", expires, signature},
+		{"other object", "uploads", "documents/b.pdf", expires, signature},
+		{"extended expiry", "uploads", "documents/a.pdf", "99999999999", signature},
+		{"tampered signature", "uploads", "documents/a.pdf", expires, strings.Repeat("0", len(signature))},
+		{"missing signature", "uploads", "documents/a.pdf", expires, ""},
+		{"malformed expiry", "uploads", "documents/a.pdf", "soon", signature},
+	}
+	for _, tt := range tests {
+		if repo.VerifySignature(tt.bucket, tt.object, tt.expires, tt.signature) {
+			t.Errorf("%s: signature accepted", tt.name)
+		}
+	}
+
+	other := New(t.TempDir(), "http://localhost/storage", []byte("other-secret"))
+	if other.VerifySignature("uploads", "documents/a.pdf", expires, signature) {
+		t.Error("signature accepted with another secret")
+	}
+}
+
+func TestVerifyPost(t *testing.T) {
+	repo := New(t.TempDir(), "http://localhost/storage", []byte("test-secret"))
+
+	postURL, fields, err := repo.PresignPost(context.Background(), "uploads", "documents/a.pdf", "application/pdf", 1, 1024, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if postURL != "http://localhost/storage/uploads" {
+		t.Errorf("PresignPost URL = %q", postURL)
+	}
+	policy, ok := repo.VerifyPost("uploads", fields)
+	if !ok {
+		t.Fatal("valid POST form rejected")
+	}
+	want := PostPolicy{Key: "documents/a.pdf", ContentType: "application/pdf", MinSize: 1, MaxSize: 1024}
+	if policy != want {
+		t.Errorf("VerifyPost policy = %+v, want %+v", policy, want)
+	}
+
+	tampered := []struct{ name, field, value string }{
+		{"other key", "key", "documents/b.pdf"},
+		{"other content type", "Content-Type", "text/html"},
+		{"raised max size", "max_size", "1073741824"},
+		{"lowered min size", "min_size", "0"},
+		{"extended expiry", "expires", "99999999999"},
+		{"tampered signature", "signature", strings.Repeat("0", 64)},
+		{"missing signature", "signature", ""},
+	}
+	for _, tt := range tampered {
+		form := make(map[string]string, len(fields))
+		for name, value := range fields {
+			form[name] = value
+		}
+		form[tt.field] = tt.value
+		if _, ok := repo.VerifyPost("uploads", form); ok {
+			t.Errorf("%s: form accepted", tt.name)
+		}
+	}
+	if _, ok := repo.VerifyPost("other", fields); ok {
+		t.Error("form accepted for another bucket")
+	}
+
+	// Moving text between signed fields must not keep the signature valid.
+	shifted := make(map[string]string, len(fields))
+	for name, value := range fields {
+		shifted[name] = value
+	}
+	shifted["key"] = fields["key"] + fields["Content-Type"][:1]
+	shifted["Content-Type"] = fields["Content-Type"][1:]
+	if _, ok := repo.VerifyPost("uploads", shifted); ok {
+		t.Error("form accepted with shifted fields")
+	}
+}
+
+func TestVerifySignatureExpired(t *testing.T) {
+	repo := New(t.TempDir(), "http://localhost/storage", []byte("test-secret"))
+
+	getURL, err := repo.PresignGet(context.Background(), "uploads", "images/a.png", -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, signature := presignedQuery(t, getURL)
+	if repo.VerifySignature("uploads", "images/a.png", expires, signature) {
+		t.Fatal("expired signature accepted")
+	}
+
+	_, fields, err := repo.PresignPost(context.Background(), "uploads", "images/a.png", "image/png", 1, 1024, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo.VerifyPost("uploads", fields); ok {
+		t.Fatal("expired POST form accepted")
+	}
+}
+
+func TestFilePathRejectsTraversal(t *testing.T) {
+	repo := New(t.TempDir(), "http://localhost/storage", []byte("test-secret"))
+
+	invalid := []struct{ bucket, object string }{
+		{"uploads", "../secret"},
+		{"uploads", ".."},
+		{"uploads", "images/../../secret"},
+		{"uploads", "/etc/passwd"},
+		{"uploads", "images//a.png"},
+		{"uploads", "images/./a.png"},
+		{"..", "a.png"},
+		{".", "a.png"},
+		{"", "a.png"},
+		{"up/loads", "a.png"},
+		{`up\loads`, "a.png"},
+	}
+	for _, tt := range invalid {
+		if _, err := repo.FilePath(tt.bucket, tt.object); !errors.Is(err, domain.ErrObjectNotFound) {
+			t.Errorf("FilePath(%q, %q) = %v, want ErrObjectNotFound", tt.bucket, tt.object, err)
+		}
+	}
+
+	if _, err := repo.FilePath("uploads", "images/products/a.png"); err != nil {
+		t.Errorf("valid key rejected: %v", err)
+	}
+	if _, err := repo.PresignGet(context.Background(), "uploads", "../secret", time.Minute); !errors.Is(err, domain.ErrObjectNotFound) {
+		t.Errorf("PresignGet signed a traversal key: %v", err)
+	}
+	if _, _, err := repo.PresignPost(context.Background(), "uploads", "../secret", "image/png", 1, 1024, time.Minute); !errors.Is(err, domain.ErrObjectNotFound) {
+		t.Errorf("PresignPost signed a traversal key: %v", err)
+	}
+}
+
+func TestPutObjectRejectsShortReader(t *testing.T) {
+	repo := New(t.TempDir(), "http://localhost/storage", []byte("test-secret"))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		data    string
+		size    int64
+		wantErr bool
+		stored  string
+	}{
+		{name: "exact", data: "hello", size: 5, stored: "hello"},
+		{name: "longer reader", data: "hello world", size: 5, stored: "hello"},
+		{name: "short reader", data: "hi", size: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "images/" + strings.ReplaceAll(tt.name, " ", "-") + ".txt"
+			err := repo.PutObject(ctx, "uploads", key, strings.NewReader(tt.data), tt.size, "text/plain")
+			if tt.wantErr {
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Fatalf("PutObject = %v, want io.ErrUnexpectedEOF", err)
+				}
+				if _, err := repo.StatObject(ctx, "uploads", key); !errors.Is(err, domain.ErrObjectNotFound) {
+					t.Fatalf("short object was stored: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PutObject: %v", err)
+			}
+			reader, err := repo.GetObject(ctx, "uploads", key)
+			if err != nil {
+				t.Fatalf("GetObject: %v", err)
+			}
+			defer reader.Close()
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != tt.stored {
+				t.Fatalf("stored %q, want %q", got, tt.stored)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package minio
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
 	"github.com/minio/minio-go/v7"
 )
 
@@ -15,6 +18,20 @@ func New(client *minio.Client) *Repository {
 	return &Repository{client: client}
 }
 
+// EnsureBucket creates the bucket unless it already exists.
+func (r *Repository) EnsureBucket(ctx context.Context, bucket, region string) error {
+	if err := r.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: region}); err != nil {
+		exists, errExists := r.client.BucketExists(ctx, bucket)
+		if errExists != nil {
+			return fmt.Errorf("check bucket: %w", errExists)
+		}
+		if !exists {
+			return fmt.Errorf("create bucket: %w", err)
+		}
+	}
+	return nil
+}
+
 func (r *Repository) PutObject(ctx context.Context, bucket, objectName string, reader io.ReadSeeker, size int64, contentType string) error {
 	_, err := r.client.PutObject(
 		ctx,
@@ -26,3 +43,98 @@ func (r *Repository) PutObject(ctx context.Context, bucket, objectName string, r
 	)
 	return err
 }
+
+// GetObject opens an object for reading. The object is looked up first so a
+// missing key is reported as domain.ErrObjectNotFound instead of on the first
+// read.
+func (r *Repository) GetObject(ctx context.Context, bucket, objectName string) (io.ReadCloser, error) {
+	return r.GetObjectRange(ctx, bucket, objectName, 0, 0)
+}
+
+// GetObjectRange opens length bytes of an object starting at offset. A
+// length of 0 reads to the end.
+func (r *Repository) GetObjectRange(ctx context.Context, bucket, objectName string, offset, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if offset > 0 || length > 0 {
+		end := int64(0)
+		if length > 0 {
+			end = offset + length - 1
+		}
+		if err := opts.SetRange(offset, end); err != nil {
+			return nil, err
+		}
+	}
+	object, err := r.client.GetObject(ctx, bucket, objectName, opts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, mapError(err)
+	}
+	return object, nil
+}
+
+// StatObject returns the size and metadata of an object.
+func (r *Repository) StatObject(ctx context.Context, bucket, objectName string) (domain.ObjectInfo, error) {
+	info, err := r.client.StatObject(ctx, bucket, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		return domain.ObjectInfo{}, mapError(err)
+	}
+	return domain.ObjectInfo{
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+// PresignPost returns the URL and form fields of a POST policy for
+// objectName. MinIO rejects the upload unless it has contentType and a size
+// between minSize and maxSize bytes.
+func (r *Repository) PresignPost(ctx context.Context, bucket, objectName, contentType string, minSize, maxSize int64, expiry time.Duration) (string, map[string]string, error) {
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(bucket); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetKey(objectName); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(expiry)); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentType(contentType); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentLengthRange(minSize, maxSize); err != nil {
+		return "", nil, err
+	}
+	u, fields, err := r.client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		return "", nil, err
+	}
+	return u.String(), fields, nil
+}
+
+// PresignGet returns a URL that downloads objectName until expiry.
+func (r *Repository) PresignGet(ctx context.Context, bucket, objectName string, expiry time.Duration) (string, error) {
+	u, err := r.client.PresignedGetObject(ctx, bucket, objectName, expiry, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// RemoveObject deletes an object. Removing a missing object is not an error.
+func (r *Repository) RemoveObject(ctx context.Context, bucket, objectName string) error {
+	return mapError(r.client.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{}))
+}
+
+func mapError(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchBucket":
+		return domain.ErrObjectNotFound
+	default:
+		return err
+	}
+}
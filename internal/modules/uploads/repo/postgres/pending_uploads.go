@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+const pendingUploadColumns = `id, object_key, module, category, original_filename, mime_type, size, uploaded_by, expires_at`
+
+func (r *UploadRepository) CreatePending(ctx context.Context, pending domain.PendingUpload) (int64, error) {
+	const query = `
+INSERT INTO public.pending_uploads (object_key, module, category, original_filename, mime_type, size, uploaded_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id`
+
+	var uploadedBy any
+	if pending.UploadedBy != "" {
+		uploadedBy = pending.UploadedBy
+	}
+	var id int64
+	err := r.DB.QueryRowContext(ctx, query,
+		pending.ObjectKey,
+		pending.Module,
+		pending.Category,
+		pending.OriginalFilename,
+		pending.MimeType,
+		pending.Size,
+		uploadedBy,
+		pending.ExpiresAt,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *UploadRepository) GetPending(ctx context.Context, id int64) (domain.PendingUpload, error) {
+	query := `SELECT ` + pendingUploadColumns + ` FROM public.pending_uploads WHERE id = $1`
+	return scanPending(r.DB.QueryRowContext(ctx, query, id))
+}
+
+// CompletePending records a direct upload and drops its pending row in one
+// transaction. It returns sql.ErrNoRows when the pending row is gone, e.g.
+// because the upload was completed concurrently.
+func (r *UploadRepository) CompletePending(ctx context.Context, id int64, upload domain.NewUpload) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM public.pending_uploads WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := insertUpload(ctx, tx, upload); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePending drops a pending upload. It reports whether the row existed.
+func (r *UploadRepository) DeletePending(ctx context.Context, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM public.pending_uploads WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// ListExpiredPending returns up to limit pending uploads whose URL expired
+// before cutoff, oldest first.
+func (r *UploadRepository) ListExpiredPending(ctx context.Context, cutoff time.Time, limit int) ([]domain.PendingUpload, error) {
+	query := `
+SELECT ` + pendingUploadColumns + `
+FROM public.pending_uploads
+WHERE expires_at < $1
+ORDER BY expires_at, id
+LIMIT $2`
+
+	rows, err := r.DB.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []domain.PendingUpload
+	for rows.Next() {
+		item, err := scanPending(rows)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, item)
+	}
+	return pending, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPending(row rowScanner) (domain.PendingUpload, error) {
+	var (
+		pending    domain.PendingUpload
+		uploadedBy sql.NullString
+	)
+	err := row.Scan(
+		&pending.ID,
+		&pending.ObjectKey,
+		&pending.Module,
+		&pending.Category,
+		&pending.OriginalFilename,
+		&pending.MimeType,
+		&pending.Size,
+		&uploadedBy,
+		&pending.ExpiresAt,
+	)
+	if err != nil {
+		return domain.PendingUpload{}, err
+	}
+	pending.UploadedBy = uploadedBy.String
+	return pending, nil
+}
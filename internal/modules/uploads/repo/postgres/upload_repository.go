@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+// unreferenced matches uploads no entity points at, neither at the upload
+// itself nor at one of its derivatives. upload_reference_keys holds every key
+// a reference may name, the reference itself or a tail of a longer URL, so
+// the check is an exact indexed lookup.
+const unreferenced = `
+NOT EXISTS (
+    SELECT 1 FROM public.upload_reference_keys r
+    WHERE r.object_key = u.object_key
+       OR r.object_key IN (SELECT jsonb_array_elements_text(u.derivatives))
+)`
+
+type UploadRepository struct {
+	DB *sql.DB
+}
+
+func NewUploadRepository(db *sql.DB) *UploadRepository {
+	return &UploadRepository{DB: db}
+}
+
+func (r *UploadRepository) CreateUpload(ctx context.Context, upload domain.NewUpload) (int64, error) {
+	return insertUpload(ctx, r.DB, upload)
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertUpload(ctx context.Context, db queryRower, upload domain.NewUpload) (int64, error) {
+	const query = `
+INSERT INTO public.uploads (object_key, module, category, original_filename, mime_type, size, checksum, uploaded_by)
+VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+RETURNING id`
+
+	var uploadedBy any
+	if upload.UploadedBy != "" {
+		uploadedBy = upload.UploadedBy
+	}
+	var id int64
+	err := db.QueryRowContext(ctx, query,
+		upload.ObjectKey,
+		upload.Module,
+		upload.Category,
+		upload.OriginalFilename,
+		upload.MimeType,
+		upload.Size,
+		upload.Checksum,
+		uploadedBy,
+	).Scan(&id)
+	return id, err
+}
+
+// AddDerivatives records derivative keys of the upload stored as objectKey,
+// keeping the ones already recorded. Objects without an upload record are
+// ignored.
+func (r *UploadRepository) AddDerivatives(ctx context.Context, objectKey string, keys []string) error {
+	keysJSON, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+UPDATE public.uploads
+SET derivatives = (
+    SELECT COALESCE(jsonb_agg(DISTINCT key ORDER BY key), '[]'::jsonb)
+    FROM jsonb_array_elements_text(derivatives || $2::jsonb) AS d(key)
+)
+WHERE object_key = $1`
+
+	_, err = r.DB.ExecContext(ctx, query, objectKey, keysJSON)
+	return err
+}
+
+// ListOrphans returns up to limit unreferenced uploads created before
+// cutoff, oldest first, leaving out uploads of the kept modules.
+func (r *UploadRepository) ListOrphans(ctx context.Context, cutoff time.Time, keptModules []string, limit int) ([]domain.Upload, error) {
+	query := `
+SELECT u.id, u.object_key, u.derivatives, u.created_at
+FROM public.uploads u
+WHERE u.created_at < $1 AND u.module <> ALL($2) AND` + unreferenced + `
+ORDER BY u.created_at, u.id
+LIMIT $3`
+
+	if keptModules == nil {
+		keptModules = []string{}
+	}
+	rows, err := r.DB.QueryContext(ctx, query, cutoff, keptModules, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uploads []domain.Upload
+	for rows.Next() {
+		var (
+			upload      domain.Upload
+			derivatives []byte
+		)
+		if err := rows.Scan(&upload.ID, &upload.ObjectKey, &derivatives, &upload.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(derivatives, &upload.Derivatives); err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+	return uploads, rows.Err()
+}
+
+// DeleteOrphan deletes the upload id if it is still unreferenced and calls
+// remove before committing, so the record survives when removing its
+// objects fails. It reports whether the upload was deleted.
+//
+// The upload row is locked first. Saving a reference locks the uploads it
+// names (see fn_sync_upload_reference_keys), so a save in flight either
+// finishes before the check below, which then sees its reference, or waits
+// until the upload is gone.
+func (r *UploadRepository) DeleteOrphan(ctx context.Context, id int64, remove func() error) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	const lockQuery = `SELECT 1 FROM public.uploads WHERE id = $1 FOR UPDATE`
+	var locked int
+	err = tx.QueryRowContext(ctx, lockQuery, id).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// A new statement, so it sees references committed while the lock was
+	// awaited.
+	query := `DELETE FROM public.uploads u WHERE u.id = $1 AND` + unreferenced
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	if err := remove(); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
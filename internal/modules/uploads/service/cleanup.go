@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+// orphanBatchSize caps the uploads removed by one cleanup run; the rest are
+// left for the next run.
+const orphanBatchSize = 500
+
+// CleanupOrphans removes uploads that are older than grace and not referenced
+// by any product, company, certification or certificate document, together
+// with their derivatives, and direct uploads whose URL expired more than
+// grace ago without being completed. The grace period leaves clients time to
+// save the entity a file was uploaded for. Uploads of modules marked
+// KeepUnreferenced are never removed. It returns the number of uploads
+// removed.
+func (s *Service) CleanupOrphans(ctx context.Context, grace time.Duration) (int, error) {
+	cutoff := time.Now().Add(-grace)
+	removed, err := s.cleanupPending(ctx, cutoff)
+	if err != nil {
+		return removed, err
+	}
+
+	orphans, err := s.records.ListOrphans(ctx, cutoff, s.keptModules(), orphanBatchSize)
+	if err != nil {
+		return removed, err
+	}
+
+	for _, orphan := range orphans {
+		deleted, err := s.records.DeleteOrphan(ctx, orphan.ID, func() error {
+			return s.removeObjects(ctx, orphan)
+		})
+		if err != nil {
+			return removed, err
+		}
+		if deleted {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// cleanupPending removes direct uploads that expired before cutoff and the
+// objects that may have been sent for them.
+func (s *Service) cleanupPending(ctx context.Context, cutoff time.Time) (int, error) {
+	pending, err := s.records.ListExpiredPending(ctx, cutoff, orphanBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, item := range pending {
+		if err := s.repo.RemoveObject(ctx, s.Bucket, item.ObjectKey); err != nil && !errors.Is(err, domain.ErrObjectNotFound) {
+			return removed, err
+		}
+		deleted, err := s.records.DeletePending(ctx, item.ID)
+		if err != nil {
+			return removed, err
+		}
+		if deleted {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// removeObjects deletes the derivatives of an upload and then the upload
+// itself. Objects that are already gone are skipped.
+func (s *Service) removeObjects(ctx context.Context, upload domain.Upload) error {
+	keys := append(append([]string{}, upload.Derivatives...), upload.ObjectKey)
+	for _, key := range keys {
+		if err := s.repo.RemoveObject(ctx, s.Bucket, key); err != nil && !errors.Is(err, domain.ErrObjectNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// keptModules lists the modules whose uploads the cleanup leaves alone.
+func (s *Service) keptModules() []string {
+	var kept []string
+	for module, config := range s.modules {
+		if config.KeepUnreferenced {
+			kept = append(kept, module)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+
+	derivativeJPEGQuality = 82
+	// maxDerivativePixels bounds the images decoded for derivatives, since a
+	// decoded image needs four bytes per pixel.
+	maxDerivativePixels = 50_000_000
+)
+
+var derivativeFormats = map[string]struct {
+	ext         string
+	contentType string
+}{
+	FormatJPEG: {".jpg", "image/jpeg"},
+	FormatPNG:  {".png", "image/png"},
+	FormatWebP: {".webp", "image/webp"},
+}
+
+// Dispatcher queues derivative generation for stored images;
+// queue.Dispatcher implements it.
+type Dispatcher interface {
+	GenerateImageDerivatives(ctx context.Context, objectName string) error
+}
+
+// planDerivatives returns the derivatives of an image stored as filename, or
+// nil when none are configured or the image type cannot be resized.
+func (s *Service) planDerivatives(filename, mimeType string) []domain.Derivative {
+	var format string
+	switch mimeType {
+	case "image/jpeg":
+		format = FormatJPEG
+	case "image/png", "image/gif", "image/webp":
+		format = FormatPNG
+	default:
+		return nil
+	}
+
+	var derivatives []domain.Derivative
+	for _, width := range s.derivatives.Widths {
+		if width <= 0 {
+			continue
+		}
+		derivatives = append(derivatives, domain.Derivative{Width: width, Format: format, Filename: derivativeFilename(filename, width, format)})
+		if s.derivatives.WebP {
+			derivatives = append(derivatives, domain.Derivative{Width: width, Format: FormatWebP, Filename: derivativeFilename(filename, width, FormatWebP)})
+		}
+	}
+	return derivatives
+}
+
+// queueDerivatives asks the worker to generate the derivatives planned for a
+// stored image and reports them on result. Queueing is best effort: when it
+// fails the original is still usable and no derivatives are reported.
+func (s *Service) queueDerivatives(ctx context.Context, objectName string, result *domain.UploadResult) {
+	derivatives := s.planDerivatives(result.Filename, result.MimeType)
+	if len(derivatives) == 0 {
+		return
+	}
+	if err := s.dispatcher.GenerateImageDerivatives(ctx, objectName); err != nil {
+		return
+	}
+	result.Derivatives = derivatives
+}
+
+// GenerateDerivatives reads the stored image objectName and writes its
+// derivatives next to it, replacing any earlier ones. Their keys are added to
+// the upload record so the cleanup job removes them with the original.
+func (s *Service) GenerateDerivatives(ctx context.Context, objectName string) error {
+	reader, err := s.repo.GetObject(ctx, s.Bucket, objectName)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return err
+	}
+
+	derivatives := s.planDerivatives(path.Base(objectName), http.DetectContentType(data))
+	if len(derivatives) == 0 {
+		return nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if config.Width*config.Height > maxDerivativePixels {
+		return fmt.Errorf("%w: %dx%d", domain.ErrImageTooLarge, config.Width, config.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	dir := path.Dir(objectName)
+	keys := make([]string, 0, len(derivatives))
+	scaled := make(map[int]image.Image)
+	for _, derivative := range derivatives {
+		img, ok := scaled[derivative.Width]
+		if !ok {
+			img = scaleToWidth(src, derivative.Width)
+			scaled[derivative.Width] = img
+		}
+
+		var buf bytes.Buffer
+		if err := encodeImage(&buf, img, derivative.Format); err != nil {
+			return err
+		}
+		key := path.Join(dir, derivative.Filename)
+		contentType := derivativeFormats[derivative.Format].contentType
+		if err := s.repo.PutObject(ctx, s.Bucket, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), contentType); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	return s.records.AddDerivatives(ctx, objectName, keys)
+}
+
+// derivativeFilename names a derivative after the original:
+// <name>_<width><ext>.
+func derivativeFilename(filename string, width int, format string) string {
+	name := strings.TrimSuffix(filename, path.Ext(filename))
+	return fmt.Sprintf("%s_%d%s", name, width, derivativeFormats[format].ext)
+}
+
+// scaleToWidth resizes src to width, keeping its aspect ratio. Images that
+// are already narrower are returned as they are.
+func scaleToWidth(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		return src
+	}
+	height := max(1, int(math.Round(float64(bounds.Dy())*float64(width)/float64(bounds.Dx()))))
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+func encodeImage(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: derivativeJPEGQuality})
+	case FormatWebP:
+		return nativewebp.Encode(w, img, nil)
+	default:
+		return png.Encode(w, img)
+	}
+}
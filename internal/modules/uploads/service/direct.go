@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+// sniffLength is the number of bytes http.DetectContentType considers.
+const sniffLength = 512
+
+// PresignUpload issues a presigned POST for a file the client sends straight
+// to storage, named like a multipart upload of the same module and checked
+// against the module policy. The POST policy holds the module's size limit,
+// so storage refuses larger files. The request is kept as a pending upload
+// until CompleteUpload registers it.
+func (s *Service) PresignUpload(ctx context.Context, req domain.DirectUploadRequest, uploadedBy string) (domain.DirectUpload, error) {
+	if req.Size <= 0 {
+		return domain.DirectUpload{}, domain.ErrEmptyFile
+	}
+	if s.direct.MaxSize > 0 && req.Size > s.direct.MaxSize {
+		return domain.DirectUpload{}, domain.ErrFileTooLarge
+	}
+	contentType := strings.TrimSpace(req.ContentType)
+	if contentType == "" {
+		return domain.DirectUpload{}, domain.ErrUnsupportedType
+	}
+
+	module := strings.ToLower(strings.TrimSpace(req.Module))
+	category, ext, err := classifyFile(contentType, req.Filename)
+	if err != nil {
+		return domain.DirectUpload{}, err
+	}
+	if err := checkFile(module, s.policyFor(module), req.Filename, category, ext, req.Size); err != nil {
+		return domain.DirectUpload{}, err
+	}
+
+	dir, filename := s.objectLocation(module, category, ext)
+	objectName := path.Join(dir, filename)
+	expiresAt := time.Now().Add(s.direct.Expiry)
+
+	url, fields, err := s.repo.PresignPost(ctx, s.Bucket, objectName, contentType, 1, s.directMaxSize(module, req.Size), s.direct.Expiry)
+	if err != nil {
+		return domain.DirectUpload{}, err
+	}
+	id, err := s.records.CreatePending(ctx, domain.PendingUpload{
+		ObjectKey:        objectName,
+		Module:           module,
+		Category:         category,
+		OriginalFilename: req.Filename,
+		MimeType:         contentType,
+		Size:             req.Size,
+		UploadedBy:       uploadedBy,
+		ExpiresAt:        expiresAt,
+	})
+	if err != nil {
+		return domain.DirectUpload{}, err
+	}
+
+	return domain.DirectUpload{
+		ID:        id,
+		Method:    http.MethodPost,
+		URL:       url,
+		Fields:    fields,
+		Directory: dir,
+		Filename:  filename,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// CompleteUpload registers a direct upload once its object is stored. Only
+// the user who requested the upload can complete it. The object is never
+// read in full: its size comes from storage and its type is sniffed from the
+// first bytes, so no checksum is recorded. An object whose size or content
+// does not match the request, or an image breaking the module's dimension
+// limits, is removed with its pending upload.
+// domain.ErrObjectNotFound means the POST has not finished yet.
+func (s *Service) CompleteUpload(ctx context.Context, id int64, uploadedBy string) (domain.UploadResult, error) {
+	pending, err := s.records.GetPending(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.UploadResult{}, domain.ErrUploadNotFound
+	}
+	if err != nil {
+		return domain.UploadResult{}, err
+	}
+	if pending.UploadedBy != uploadedBy {
+		return domain.UploadResult{}, domain.ErrUploadNotFound
+	}
+
+	info, err := s.repo.StatObject(ctx, s.Bucket, pending.ObjectKey)
+	if err != nil {
+		return domain.UploadResult{}, err
+	}
+	if info.Size != pending.Size {
+		s.discardPending(ctx, pending)
+		return domain.UploadResult{}, domain.ErrUploadMismatch
+	}
+
+	contentType, err := s.sniffObject(ctx, pending.ObjectKey)
+	if err != nil {
+		return domain.UploadResult{}, err
+	}
+	if category, _, err := classifyFile(contentType, pending.OriginalFilename); err != nil || category != pending.Category {
+		s.discardPending(ctx, pending)
+		return domain.UploadResult{}, domain.ErrUploadMismatch
+	}
+	if err := s.checkStoredDimensions(ctx, pending); err != nil {
+		var policyErr *domain.PolicyError
+		if errors.As(err, &policyErr) {
+			s.discardPending(ctx, pending)
+		}
+		return domain.UploadResult{}, err
+	}
+
+	err = s.records.CompletePending(ctx, pending.ID, domain.NewUpload{
+		ObjectKey:        pending.ObjectKey,
+		Module:           pending.Module,
+		Category:         pending.Category,
+		OriginalFilename: pending.OriginalFilename,
+		MimeType:         contentType,
+		Size:             info.Size,
+		UploadedBy:       pending.UploadedBy,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.UploadResult{}, domain.ErrUploadNotFound
+	}
+	if err != nil {
+		return domain.UploadResult{}, err
+	}
+
+	result := domain.UploadResult{
+		Directory:        path.Dir(pending.ObjectKey),
+		Filename:         path.Base(pending.ObjectKey),
+		OriginalFilename: pending.OriginalFilename,
+		MimeType:         contentType,
+		Size:             info.Size,
+		Category:         pending.Category,
+	}
+	s.queueDerivatives(ctx, pending.ObjectKey, &result)
+	return result, nil
+}
+
+// checkStoredDimensions applies the image size limits of the module to a
+// directly uploaded image.
+func (s *Service) checkStoredDimensions(ctx context.Context, pending domain.PendingUpload) error {
+	policy := s.policyFor(pending.Module)
+	if pending.Category != domain.CategoryImages || (policy.MaxWidth <= 0 && policy.MaxHeight <= 0) {
+		return nil
+	}
+	reader, err := s.repo.GetObject(ctx, s.Bucket, pending.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return checkDimensions(pending.Module, policy, pending.OriginalFilename, reader)
+}
+
+// discardPending removes a rejected direct upload. It is best effort; the
+// cleanup task catches whatever is left.
+func (s *Service) discardPending(ctx context.Context, pending domain.PendingUpload) {
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.RemoveObject(ctx, s.Bucket, pending.ObjectKey); err != nil && !errors.Is(err, domain.ErrObjectNotFound) {
+		return
+	}
+	_, _ = s.records.DeletePending(ctx, pending.ID)
+}
+
+// directMaxSize is the largest direct upload a module accepts. Without any
+// configured limit the upload is held to its declared size.
+func (s *Service) directMaxSize(module string, declared int64) int64 {
+	maxSize := s.policyFor(module).MaxSize
+	if s.direct.MaxSize > 0 && (maxSize <= 0 || s.direct.MaxSize < maxSize) {
+		maxSize = s.direct.MaxSize
+	}
+	if maxSize <= 0 {
+		return declared
+	}
+	return maxSize
+}
+
+// sniffObject detects the content type of a stored object from its first
+// 512 bytes.
+func (s *Service) sniffObject(ctx context.Context, objectKey string) (string, error) {
+	reader, err := s.repo.GetObjectRange(ctx, s.Bucket, objectKey, 0, sniffLength)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	head, err := io.ReadAll(io.LimitReader(reader, sniffLength))
+	if err != nil {
+		return "", err
+	}
+	return http.DetectContentType(head), nil
+}
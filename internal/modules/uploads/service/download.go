@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+// ResolveDownload validates an object key taken from a download URL and
+// reports whether it lies under a module with a private policy. Keys that are not in
+// canonical form, such as ones containing "..", are reported as
+// domain.ErrObjectNotFound.
+func (s *Service) ResolveDownload(key string) (string, bool, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", false, domain.ErrObjectNotFound
+	}
+	for module, config := range s.modules {
+		if config.Policy.Private && strings.HasPrefix(key, s.resolveBasePath(module, "")+"/") {
+			return key, true, nil
+		}
+	}
+	return key, false, nil
+}
+
+// StatFile describes the stored object key.
+func (s *Service) StatFile(ctx context.Context, key string) (domain.ObjectInfo, error) {
+	return s.repo.StatObject(ctx, s.Bucket, key)
+}
+
+// OpenFile opens length bytes of the stored object key starting at offset.
+// A length of 0 reads to the end.
+func (s *Service) OpenFile(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	return s.repo.GetObjectRange(ctx, s.Bucket, key, offset, length)
+}
+
+// PresignDownload returns a URL that downloads the stored object key until
+// expiry.
+func (s *Service) PresignDownload(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := s.repo.StatObject(ctx, s.Bucket, key); err != nil {
+		return "", err
+	}
+	return s.repo.PresignGet(ctx, s.Bucket, key, expiry)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"image"
+	"io"
+	"slices"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+)
+
+// policyFor returns the policy of module, or domain.DefaultPolicy when the
+// module has no configuration of its own.
+func (s *Service) policyFor(module string) domain.Policy {
+	if config, ok := s.modules[module]; ok {
+		return config.Policy
+	}
+	return domain.DefaultPolicy
+}
+
+// checkCount enforces the per-request file limit of a module.
+func checkCount(module string, policy domain.Policy, count int) error {
+	if policy.MaxFiles > 0 && count > policy.MaxFiles {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleMaxFiles, Limit: policy.MaxFiles, Value: count}
+	}
+	return nil
+}
+
+// checkFile enforces the type and size rules of a module for one file.
+func checkFile(module string, policy domain.Policy, filename, category, ext string, size int64) error {
+	if len(policy.Categories) > 0 && !slices.Contains(policy.Categories, category) {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleCategory, File: filename, Limit: policy.Categories, Value: category}
+	}
+	if len(policy.Extensions) > 0 && !slices.Contains(policy.Extensions, ext) {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleExtension, File: filename, Limit: policy.Extensions, Value: ext}
+	}
+	if policy.MaxSize > 0 && size > policy.MaxSize {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleMaxSize, File: filename, Limit: policy.MaxSize, Value: size}
+	}
+	return nil
+}
+
+// checkDimensions enforces the image size limits of a module, reading only
+// the image header from r. Images whose header cannot be decoded are
+// rejected, since their size cannot be checked.
+func checkDimensions(module string, policy domain.Policy, filename string, r io.Reader) error {
+	if policy.MaxWidth <= 0 && policy.MaxHeight <= 0 {
+		return nil
+	}
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleImageDecode, File: filename, Limit: "decodable image", Value: err.Error()}
+	}
+	if policy.MaxWidth > 0 && config.Width > policy.MaxWidth {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleMaxWidth, File: filename, Limit: policy.MaxWidth, Value: config.Width}
+	}
+	if policy.MaxHeight > 0 && config.Height > policy.MaxHeight {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleMaxHeight, File: filename, Limit: policy.MaxHeight, Value: config.Height}
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +12,7 @@ import (
 	"net/http"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,43 +22,90 @@ import (
 
 type Service struct {
 	repo        StorageRepository
+	records     MetadataRepository
 	Bucket      string
 	basePath    string
-	modulePaths map[string]string
+	modules     map[string]domain.ModuleConfig
+	derivatives domain.DerivativeConfig
+	direct      domain.DirectUploadConfig
+	dispatcher  Dispatcher
 }
 
 type StorageRepository interface {
 	PutObject(ctx context.Context, bucket, objectName string, reader io.ReadSeeker, size int64, contentType string) error
+	// GetObject opens a stored object. It returns domain.ErrObjectNotFound
+	// when the object does not exist.
+	GetObject(ctx context.Context, bucket, objectName string) (io.ReadCloser, error)
+	// GetObjectRange opens length bytes of an object starting at offset; a
+	// length of 0 reads to the end.
+	GetObjectRange(ctx context.Context, bucket, objectName string, offset, length int64) (io.ReadCloser, error)
+	// StatObject describes a stored object. It returns
+	// domain.ErrObjectNotFound when the object does not exist.
+	StatObject(ctx context.Context, bucket, objectName string) (domain.ObjectInfo, error)
+	// PresignPost returns a URL and the form fields of a POST that uploads
+	// objectName until expiry. Storage rejects the upload unless it has the
+	// given Content-Type and a size between minSize and maxSize.
+	PresignPost(ctx context.Context, bucket, objectName, contentType string, minSize, maxSize int64, expiry time.Duration) (string, map[string]string, error)
+	// PresignGet returns a URL that downloads objectName until expiry.
+	PresignGet(ctx context.Context, bucket, objectName string, expiry time.Duration) (string, error)
+	// RemoveObject deletes a stored object. Removing a missing object is not
+	// an error.
+	RemoveObject(ctx context.Context, bucket, objectName string) error
 }
 
-func New(repo StorageRepository, bucket, basePath string, modulePaths map[string]string) *Service {
+// MetadataRepository records stored objects so unreferenced ones can be
+// found and removed.
+type MetadataRepository interface {
+	CreateUpload(ctx context.Context, upload domain.NewUpload) (int64, error)
+	AddDerivatives(ctx context.Context, objectKey string, keys []string) error
+	ListOrphans(ctx context.Context, cutoff time.Time, keptModules []string, limit int) ([]domain.Upload, error)
+	DeleteOrphan(ctx context.Context, id int64, remove func() error) (bool, error)
+	CreatePending(ctx context.Context, pending domain.PendingUpload) (int64, error)
+	GetPending(ctx context.Context, id int64) (domain.PendingUpload, error)
+	CompletePending(ctx context.Context, id int64, upload domain.NewUpload) error
+	DeletePending(ctx context.Context, id int64) (bool, error)
+	ListExpiredPending(ctx context.Context, cutoff time.Time, limit int) ([]domain.PendingUpload, error)
+}
+
+func New(repo StorageRepository, records MetadataRepository, bucket, basePath string, modules map[string]domain.ModuleConfig, derivatives domain.DerivativeConfig, direct domain.DirectUploadConfig, dispatcher Dispatcher) *Service {
 	cleanBase := strings.Trim(basePath, "/")
-	normalized := make(map[string]string, len(modulePaths))
-	for key, value := range modulePaths {
+	normalized := make(map[string]domain.ModuleConfig, len(modules))
+	for key, value := range modules {
 		k := strings.ToLower(strings.TrimSpace(key))
 		if k == "" {
 			continue
 		}
-		normalized[k] = strings.Trim(value, "/")
+		value.Path = strings.Trim(value.Path, "/")
+		normalized[k] = value
 	}
 	return &Service{
 		repo:        repo,
+		records:     records,
 		Bucket:      bucket,
 		basePath:    cleanBase,
-		modulePaths: normalized,
+		modules:     normalized,
+		derivatives: derivatives,
+		direct:      direct,
+		dispatcher:  dispatcher,
 	}
 }
 
-func (s *Service) UploadFiles(ctx context.Context, module string, fileHeaders []*multipart.FileHeader) ([]domain.UploadResult, error) {
+// UploadFiles stores each file under the module path and records it as
+// uploaded by uploadedBy, the uploader's xid. Files are checked against the
+// module policy; a violation is returned as a *domain.PolicyError.
+func (s *Service) UploadFiles(ctx context.Context, module string, fileHeaders []*multipart.FileHeader, uploadedBy string) ([]domain.UploadResult, error) {
 	if len(fileHeaders) == 0 {
 		return nil, domain.ErrNoFilesProvided
 	}
 
 	module = strings.ToLower(strings.TrimSpace(module))
+	if err := checkCount(module, s.policyFor(module), len(fileHeaders)); err != nil {
+		return nil, err
+	}
 
 	results := make([]domain.UploadResult, 0, len(fileHeaders))
 	for _, fh := range fileHeaders {
-		result, err := s.uploadSingle(ctx, module, fh)
+		result, err := s.uploadSingle(ctx, module, fh, uploadedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -63,7 +114,35 @@ func (s *Service) UploadFiles(ctx context.Context, module string, fileHeaders []
 	return results, nil
 }
 
-func (s *Service) uploadSingle(ctx context.Context, module string, fileHeader *multipart.FileHeader) (domain.UploadResult, error) {
+// CheckFileCategory sniffs the content of a file and returns a
+// *domain.PolicyError for the category rule unless it falls in one of
+// categories. Callers that accept only some kinds of file run it before
+// UploadFiles, so a rejected file is never stored.
+func (s *Service) CheckFileCategory(module string, fileHeader *multipart.FileHeader, categories ...string) error {
+	if fileHeader == nil || fileHeader.Size == 0 {
+		return domain.ErrEmptyFile
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	contentType, err := detectContentType(file)
+	if err != nil {
+		return err
+	}
+	category, _, err := classifyFile(contentType, fileHeader.Filename)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(categories, category) {
+		return &domain.PolicyError{Module: module, Rule: domain.RuleCategory, File: fileHeader.Filename, Limit: categories, Value: category}
+	}
+	return nil
+}
+
+func (s *Service) uploadSingle(ctx context.Context, module string, fileHeader *multipart.FileHeader, uploadedBy string) (domain.UploadResult, error) {
 	if fileHeader == nil {
 		return domain.UploadResult{}, domain.ErrEmptyFile
 	}
@@ -86,41 +165,119 @@ func (s *Service) uploadSingle(ctx context.Context, module string, fileHeader *m
 	if err != nil {
 		return domain.UploadResult{}, err
 	}
+	policy := s.policyFor(module)
+	if err := checkFile(module, policy, fileHeader.Filename, category, ext, fileHeader.Size); err != nil {
+		return domain.UploadResult{}, err
+	}
+	if category == domain.CategoryImages {
+		if err := checkDimensions(module, policy, fileHeader.Filename, file); err != nil {
+			return domain.UploadResult{}, err
+		}
+	}
 
-	basePath := s.resolveBasePath(module, category)
-	now := time.Now().UTC()
-	datePath := fmt.Sprintf("%d/%02d/%02d", now.Year(), now.Month(), now.Day())
-	dir := path.Join(basePath, datePath)
-
-	filename := xid.New().String() + ext
-	objectName := path.Join(dir, filename)
-
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
+	checksum, err := sha256Hex(file)
+	if err != nil {
 		return domain.UploadResult{}, err
 	}
 
+	dir, filename := s.objectLocation(module, category, ext)
+	objectName := path.Join(dir, filename)
+
 	if err := s.repo.PutObject(ctx, s.Bucket, objectName, file, fileHeader.Size, contentType); err != nil {
 		return domain.UploadResult{}, err
 	}
 
-	return domain.UploadResult{
+	result := domain.UploadResult{
 		Directory:        dir,
 		Filename:         filename,
 		OriginalFilename: fileHeader.Filename,
 		MimeType:         contentType,
 		Size:             fileHeader.Size,
 		Category:         category,
-	}, nil
+		Checksum:         checksum,
+	}
+	if err := s.record(ctx, module, result, uploadedBy); err != nil {
+		return domain.UploadResult{}, err
+	}
+	s.queueDerivatives(ctx, objectName, &result)
+	return result, nil
+}
+
+// UploadBytes stores generated content, such as a rendered PDF, under the
+// module path with the same naming scheme as uploaded files. filename only
+// supplies the extension and the reported original name. The content is
+// trusted, so the module policy does not apply.
+func (s *Service) UploadBytes(ctx context.Context, module, filename string, data []byte) (domain.UploadResult, error) {
+	if len(data) == 0 {
+		return domain.UploadResult{}, domain.ErrEmptyFile
+	}
+
+	module = strings.ToLower(strings.TrimSpace(module))
+	contentType := http.DetectContentType(data)
+	category, ext, err := classifyFile(contentType, filename)
+	if err != nil {
+		return domain.UploadResult{}, err
+	}
+
+	dir, storedName := s.objectLocation(module, category, ext)
+	size := int64(len(data))
+	if err := s.repo.PutObject(ctx, s.Bucket, path.Join(dir, storedName), bytes.NewReader(data), size, contentType); err != nil {
+		return domain.UploadResult{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	result := domain.UploadResult{
+		Directory:        dir,
+		Filename:         storedName,
+		OriginalFilename: filename,
+		MimeType:         contentType,
+		Size:             size,
+		Category:         category,
+		Checksum:         hex.EncodeToString(sum[:]),
+	}
+	if err := s.record(ctx, module, result, ""); err != nil {
+		return domain.UploadResult{}, err
+	}
+	return result, nil
+}
+
+// record writes the upload record of a stored object. The object is removed
+// again when that fails, since the cleanup job only sees recorded objects.
+func (s *Service) record(ctx context.Context, module string, result domain.UploadResult, uploadedBy string) error {
+	objectName := path.Join(result.Directory, result.Filename)
+	_, err := s.records.CreateUpload(ctx, domain.NewUpload{
+		ObjectKey:        objectName,
+		Module:           module,
+		Category:         result.Category,
+		OriginalFilename: result.OriginalFilename,
+		MimeType:         result.MimeType,
+		Size:             result.Size,
+		Checksum:         result.Checksum,
+		UploadedBy:       uploadedBy,
+	})
+	if err != nil {
+		_ = s.repo.RemoveObject(context.WithoutCancel(ctx), s.Bucket, objectName)
+		return err
+	}
+	return nil
+}
+
+// objectLocation returns the dated directory and the unique file name a new
+// object of the given category is stored under.
+func (s *Service) objectLocation(module, category, ext string) (string, string) {
+	now := time.Now().UTC()
+	datePath := fmt.Sprintf("%d/%02d/%02d", now.Year(), now.Month(), now.Day())
+	return path.Join(s.resolveBasePath(module, category), datePath), xid.New().String() + ext
 }
 
 func (s *Service) resolveBasePath(module, category string) string {
 	root := s.basePath
 	if module != "" {
-		if modulePath, ok := s.modulePaths[module]; ok && modulePath != "" {
+		if config, ok := s.modules[module]; ok && config.Path != "" {
 			if root != "" {
-				return path.Join(root, modulePath)
+				return path.Join(root, config.Path)
 			}
-			return modulePath
+			return config.Path
 		}
 		if root != "" {
 			return path.Join(root, module, category)
@@ -134,6 +291,21 @@ func (s *Service) resolveBasePath(module, category string) string {
 	return category
 }
 
+// sha256Hex hashes the content of r and rewinds it.
+func sha256Hex(r io.ReadSeeker) (string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 func detectContentType(file multipart.File) (string, error) {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		return "", err
@@ -161,40 +333,40 @@ func classifyFile(mimeType, originalFilename string) (string, string, error) {
 	ext := strings.ToLower(filepath.Ext(originalFilename))
 	switch {
 	case strings.HasPrefix(mimeType, "image/"):
-		if ext == "" {
-			ext = extensionFromMime(mimeType)
-		}
+		// The stored name decides the type files are served with, so it
+		// follows the sniffed type rather than the client's name.
+		ext = extensionFromMime(mimeType)
 		if ext == "" {
 			ext = ".img"
 		}
-		return "images", ext, nil
+		return domain.CategoryImages, ext, nil
 	case strings.HasPrefix(mimeType, "video/"):
 		if ext == "" {
 			ext = extensionFromMime(mimeType)
 		}
 		if isAllowedVideoExt(ext) {
-			return "videos", ext, nil
+			return domain.CategoryVideos, ext, nil
 		}
 		return "", "", domain.ErrUnsupportedType
 	case mimeType == "application/pdf":
-		return "documents", ".pdf", nil
+		return domain.CategoryDocuments, ".pdf", nil
 	case mimeType == "application/zip" || mimeType == "application/x-zip-compressed":
-		return "archives", ".zip", nil
+		return domain.CategoryArchives, ".zip", nil
 	case mimeType == "application/x-rar-compressed" || mimeType == "application/vnd.rar":
-		return "archives", ".rar", nil
+		return domain.CategoryArchives, ".rar", nil
 	}
 
 	switch ext {
 	case ".pdf":
-		return "documents", ".pdf", nil
+		return domain.CategoryDocuments, ".pdf", nil
 	case ".zip":
-		return "archives", ".zip", nil
+		return domain.CategoryArchives, ".zip", nil
 	case ".rar":
-		return "archives", ".rar", nil
+		return domain.CategoryArchives, ".rar", nil
 	case ".mp4", ".mov", ".avi", ".mkv", ".webm":
-		return "videos", ext, nil
+		return domain.CategoryVideos, ext, nil
 	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
-		return "images", ext, nil
+		return domain.CategoryImages, ext, nil
 	default:
 		return "", "", domain.ErrUnsupportedType
 	}
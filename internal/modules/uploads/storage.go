@@ -0,0 +1,64 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/config"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/repo/filesystem"
+	miniorepo "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/repo/minio"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/service"
+)
+
+// Storage is the object store behind the uploads service.
+type Storage interface {
+	service.StorageRepository
+	// EnsureBucket creates bucket unless it already exists.
+	EnsureBucket(ctx context.Context, bucket, region string) error
+}
+
+// NewStorage opens the storage driver selected by cfg.Driver.
+func NewStorage(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case config.StorageDriverMinio:
+		client, err := minio.New(cfg.Endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+			Secure: cfg.UseSSL,
+			Region: cfg.Region,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return miniorepo.New(client), nil
+	case config.StorageDriverFilesystem:
+		if err := checkSigningSecret(cfg); err != nil {
+			return nil, err
+		}
+		return filesystem.New(cfg.Root, cfg.FilesystemURL, []byte(cfg.FilesystemSecret)), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// weakSecrets are defaults shipped in examples and documentation.
+var weakSecrets = []string{"minioadmin", "changeme", "secret", "password"}
+
+// checkSigningSecret refuses filesystem URL signing secrets that are missing,
+// well known or shared with the MinIO credentials: anyone knowing the secret
+// can read private files and overwrite any object.
+func checkSigningSecret(cfg config.StorageConfig) error {
+	secret := cfg.FilesystemSecret
+	if strings.TrimSpace(secret) == "" {
+		return errors.New("STORAGE_FS_SECRET is required for the filesystem storage driver")
+	}
+	if slices.Contains(weakSecrets, strings.ToLower(secret)) || secret == cfg.SecretKey {
+		return errors.New("STORAGE_FS_SECRET must not be a default or the storage secret key")
+	}
+	return nil
+}
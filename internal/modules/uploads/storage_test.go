@@ -0,0 +1,30 @@
+package uploads
+
+import (
+	"testing"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/config"
+)
+
+func TestNewStorageRequiresFilesystemSecret(t *testing.T) {
+	base := config.StorageConfig{
+		Driver:        config.StorageDriverFilesystem,
+		Root:          t.TempDir(),
+		FilesystemURL: "http://localhost/storage",
+		SecretKey:     "minio-secret-key",
+	}
+
+	for _, secret := range []string{"", "  ", "minioadmin", "changeme", "minio-secret-key"} {
+		cfg := base
+		cfg.FilesystemSecret = secret
+		if _, err := NewStorage(cfg); err == nil {
+			t.Errorf("NewStorage accepted secret %q", secret)
+		}
+	}
+
+	cfg := base
+	cfg.FilesystemSecret = "0f4c2d9b6e1a8f3c7d5e2b9a4c6f8d1e"
+	if _, err := NewStorage(cfg); err != nil {
+		t.Errorf("NewStorage rejected a strong secret: %v", err)
+	}
+}
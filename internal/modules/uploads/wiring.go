@@ -1,21 +1,24 @@
 package uploads
 
 import (
-	"github.com/minio/minio-go/v7"
+	"database/sql"
 
-	miniorepo "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/repo/minio"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/repo/postgres"
 	"github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/service"
 )
 
 type Module struct {
-	Repository *miniorepo.Repository
-	Service    *service.Service
+	Repository       Storage
+	UploadRepository *postgres.UploadRepository
+	Service          *service.Service
 }
 
-func Provide(client *minio.Client, bucket, basePath string, modulePaths map[string]string) *Module {
-	repo := miniorepo.New(client)
+func Provide(db *sql.DB, storage Storage, bucket, basePath string, modules map[string]domain.ModuleConfig, derivatives domain.DerivativeConfig, direct domain.DirectUploadConfig, dispatcher service.Dispatcher) *Module {
+	uploadRepo := postgres.NewUploadRepository(db)
 	return &Module{
-		Repository: repo,
-		Service:    service.New(repo, bucket, basePath, modulePaths),
+		Repository:       storage,
+		UploadRepository: uploadRepo,
+		Service:          service.New(storage, uploadRepo, bucket, basePath, modules, derivatives, direct, dispatcher),
 	}
 }
@@ -94,12 +94,14 @@ func (repository *UserRepository) List(ctx context.Context) ([]domain.User, erro
 	return users, nil
 }
 
+// Update clears email_verified_at when the email address changes.
 func (repository *UserRepository) Update(ctx context.Context, xid, name, email string, passwordHash *string, isActive bool) (*domain.User, error) {
 	var row rowScanner
 	if passwordHash != nil {
 		row = repository.DB.QueryRowContext(ctx,
 			`UPDATE users
-             SET name=$1, email=$2, password=$3, is_active=$4, updated_at=NOW()
+             SET name=$1, email=$2, password=$3, is_active=$4, updated_at=NOW(),
+                 email_verified_at = CASE WHEN email = $2 THEN email_verified_at END
              WHERE xid=$5 AND deleted_at IS NULL
              RETURNING id, xid, name, email, password, is_active, email_verified_at, created_at, updated_at`,
 			name, email, *passwordHash, isActive, xid,
@@ -107,7 +109,8 @@ func (repository *UserRepository) Update(ctx context.Context, xid, name, email s
 	} else {
 		row = repository.DB.QueryRowContext(ctx,
 			`UPDATE users
-             SET name=$1, email=$2, is_active=$3, updated_at=NOW()
+             SET name=$1, email=$2, is_active=$3, updated_at=NOW(),
+                 email_verified_at = CASE WHEN email = $2 THEN email_verified_at END
              WHERE xid=$4 AND deleted_at IS NULL
              RETURNING id, xid, name, email, password, is_active, email_verified_at, created_at, updated_at`,
 			name, email, isActive, xid,
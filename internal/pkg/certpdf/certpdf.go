@@ -0,0 +1,162 @@
+// Package certpdf renders official certificate documents as PDF.
+package certpdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+var ErrMissingNumber = errors.New("certpdf: certificate number is required")
+
+// Certificate is the data printed on a certificate document.
+type Certificate struct {
+	ProgramCode   string
+	ProgramName   string
+	CertificateNo string
+	Certification string
+	Product       string
+	Brand         string
+	Company       string
+	IssueDate     *time.Time
+	ExpiryDate    *time.Time
+}
+
+// Template holds the wording and colours of one program's certificate.
+type Template struct {
+	Title     string
+	Subtitle  string
+	Statement string
+	Issuer    string
+	Accent    [3]int
+}
+
+var defaultTemplate = Template{
+	Title:     "CERTIFICATE",
+	Subtitle:  "Green Product Council Indonesia",
+	Statement: "This is to certify that the product below has been assessed and meets the requirements of",
+	Issuer:    "Green Product Council Indonesia",
+	Accent:    [3]int{46, 125, 50},
+}
+
+var templates = map[string]Template{
+	"green_label": {
+		Title:     "GREEN LABEL CERTIFICATE",
+		Subtitle:  "Green Label Indonesia",
+		Statement: "This is to certify that the product below has been assessed and meets the Green Label criteria of",
+		Issuer:    "Green Product Council Indonesia",
+		Accent:    [3]int{46, 125, 50},
+	},
+	"green_toll": {
+		Title:     "GREEN TOLL CERTIFICATE",
+		Subtitle:  "Green Toll Road Indonesia",
+		Statement: "This is to certify that the product below has been assessed and meets the Green Toll criteria of",
+		Issuer:    "Green Product Council Indonesia",
+		Accent:    [3]int{21, 101, 192},
+	},
+}
+
+// TemplateFor returns the template registered for programCode, falling back
+// to a generic certificate layout.
+func TemplateFor(programCode string) Template {
+	if tpl, ok := templates[programCode]; ok {
+		return tpl
+	}
+	return defaultTemplate
+}
+
+// Render lays out cert on a single A4 landscape page using its program
+// template and returns the PDF bytes.
+func Render(cert Certificate) ([]byte, error) {
+	if cert.CertificateNo == "" {
+		return nil, ErrMissingNumber
+	}
+	tpl := TemplateFor(cert.ProgramCode)
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("%s %s", tpl.Title, cert.CertificateNo), true)
+	pdf.SetAuthor(tpl.Issuer, true)
+	pdf.SetCreationDate(time.Now().UTC())
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	width, height := pdf.GetPageSize()
+	red, green, blue := tpl.Accent[0], tpl.Accent[1], tpl.Accent[2]
+
+	pdf.SetDrawColor(red, green, blue)
+	pdf.SetLineWidth(2)
+	pdf.Rect(10, 10, width-20, height-20, "D")
+	pdf.SetLineWidth(0.5)
+	pdf.Rect(14, 14, width-28, height-28, "D")
+
+	pdf.SetTextColor(red, green, blue)
+	pdf.SetFont("Helvetica", "B", 30)
+	pdf.SetXY(20, 32)
+	pdf.CellFormat(width-40, 14, tr(tpl.Title), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.SetX(20)
+	pdf.CellFormat(width-40, 8, tr(tpl.Subtitle), "", 1, "C", false, 0, "")
+
+	pdf.SetTextColor(60, 60, 60)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetXY(20, 62)
+	pdf.CellFormat(width-40, 6, tr("Certificate No. "+cert.CertificateNo), "", 1, "C", false, 0, "")
+
+	pdf.SetXY(40, 76)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.MultiCell(width-80, 6, tr(tpl.Statement), "", "C", false)
+	pdf.SetX(40)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.SetTextColor(red, green, blue)
+	pdf.MultiCell(width-80, 9, tr(cert.Certification), "", "C", false)
+
+	pdf.SetTextColor(30, 30, 30)
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.SetX(20)
+	pdf.Ln(4)
+	pdf.MultiCell(width-40, 11, tr(cert.Product), "", "C", false)
+
+	rows := [][2]string{
+		{"Brand", cert.Brand},
+		{"Company", cert.Company},
+		{"Program", cert.ProgramName},
+		{"Valid from", formatDate(cert.IssueDate)},
+		{"Valid until", formatDate(cert.ExpiryDate)},
+	}
+	pdf.Ln(4)
+	labelWidth, valueWidth := 45.0, 110.0
+	left := (width - labelWidth - valueWidth) / 2
+	for _, row := range rows {
+		pdf.SetX(left)
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(labelWidth, 7, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.SetTextColor(30, 30, 30)
+		pdf.CellFormat(valueWidth, 7, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.SetXY(20, height-34)
+	pdf.CellFormat(width-40, 6, tr("Issued by "+tpl.Issuer), "", 1, "C", false, 0, "")
+	pdf.SetX(20)
+	pdf.CellFormat(width-40, 6, tr("Verify this certificate by its number on the "+tpl.Issuer+" website."), "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render certificate pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2 January 2006")
+}
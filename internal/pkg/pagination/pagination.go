@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid_cursor")
+
+// Cursor marks the last row of a page in a list ordered by a timestamp and
+// then by ID, both descending. Clients only see it as an opaque string.
+type Cursor struct {
+	Time time.Time
+	ID   int64
+}
+
+type cursorToken struct {
+	Time time.Time `json:"t"`
+	ID   int64     `json:"id"`
+}
+
+// Encode returns the opaque form of the cursor handed out as next_cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(cursorToken{Time: c.Time, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a cursor produced by Encode.
+func Decode(value string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID <= 0 || token.Time.IsZero() {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Time: token.Time, ID: token.ID}, nil
+}
+
+// Condition appends the cursor to args and returns the condition selecting
+// the rows that follow it when ordered by timeColumn DESC, idColumn DESC.
+func (c Cursor) Condition(timeColumn, idColumn string, args *[]any) string {
+	*args = append(*args, c.Time, c.ID)
+	return fmt.Sprintf("(%s, %s) < ($%d, $%d)", timeColumn, idColumn, len(*args)-1, len(*args))
+}
+
+// Result describes a page besides its items.
+type Result struct {
+	// Total is nil when counting was skipped.
+	Total *int
+	// Next points at the following page and is nil on the last one.
+	Next *Cursor
+}
+
+// NextCursor returns the encoded cursor of the following page, or "" on the
+// last page.
+func (r Result) NextCursor() string {
+	if r.Next == nil {
+		return ""
+	}
+	return r.Next.Encode()
+}
+
+// Trim is used with queries that fetch one row more than limit to find out
+// whether another page follows. It drops that row and returns the cursor of
+// the last row kept, or nil when there is no following page.
+func Trim[T any](items []T, limit int, key func(T) Cursor) ([]T, *Cursor) {
+	if limit <= 0 || len(items) <= limit {
+		return items, nil
+	}
+	items = items[:limit]
+	next := key(items[limit-1])
+	return items, &next
+}
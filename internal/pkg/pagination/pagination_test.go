@@ -0,0 +1,97 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	cursors := []Cursor{
+		{Time: time.Date(2025, 10, 14, 8, 30, 0, 123456789, time.UTC), ID: 1},
+		{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta), ID: 9007199254740993},
+	}
+	for _, cursor := range cursors {
+		got, err := Decode(cursor.Encode())
+		if err != nil {
+			t.Fatalf("Decode(%v.Encode()): %v", cursor, err)
+		}
+		if got.ID != cursor.ID || !got.Time.Equal(cursor.Time) {
+			t.Errorf("round trip of %v = %v", cursor, got)
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidCursors(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	invalid := map[string]string{
+		"empty":          "",
+		"not base64":     "!!!",
+		"padded base64":  base64.URLEncoding.EncodeToString([]byte(`{"t":"2025-10-14T08:30:00Z","id":1}`)),
+		"not json":       encode("hello"),
+		"missing id":     encode(`{"t":"2025-10-14T08:30:00Z"}`),
+		"zero id":        encode(`{"t":"2025-10-14T08:30:00Z","id":0}`),
+		"negative id":    encode(`{"t":"2025-10-14T08:30:00Z","id":-5}`),
+		"missing time":   encode(`{"id":1}`),
+		"malformed time": encode(`{"t":"yesterday","id":1}`),
+	}
+	for name, value := range invalid {
+		if _, err := Decode(value); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: Decode(%q) = %v, want ErrInvalidCursor", name, value, err)
+		}
+	}
+}
+
+func TestCondition(t *testing.T) {
+	cursor := Cursor{Time: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), ID: 7}
+	args := []any{"search"}
+	got := cursor.Condition("p.updated_at", "p.id", &args)
+	if want := "(p.updated_at, p.id) < ($2, $3)"; got != want {
+		t.Errorf("Condition = %q, want %q", got, want)
+	}
+	if len(args) != 3 || args[1] != cursor.Time || args[2] != cursor.ID {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	key := func(id int) Cursor { return Cursor{Time: time.Unix(int64(id), 0), ID: int64(id)} }
+	tests := []struct {
+		name     string
+		items    []int
+		limit    int
+		want     []int
+		wantNext int64
+	}{
+		{name: "empty", items: nil, limit: 3, want: nil},
+		{name: "short page", items: []int{5, 4}, limit: 3, want: []int{5, 4}},
+		{name: "exact page", items: []int{5, 4, 3}, limit: 3, want: []int{5, 4, 3}},
+		{name: "extra row", items: []int{5, 4, 3, 2}, limit: 3, want: []int{5, 4, 3}, wantNext: 3},
+		{name: "no limit", items: []int{5, 4, 3, 2}, limit: 0, want: []int{5, 4, 3, 2}},
+	}
+	for _, tt := range tests {
+		got, next := Trim(tt.items, tt.limit, key)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: items = %v, want %v", tt.name, got, tt.want)
+		}
+		switch {
+		case tt.wantNext == 0 && next != nil:
+			t.Errorf("%s: next = %v, want nil", tt.name, *next)
+		case tt.wantNext != 0 && (next == nil || next.ID != tt.wantNext):
+			t.Errorf("%s: next = %v, want ID %d", tt.name, next, tt.wantNext)
+		}
+	}
+}
+
+func TestResultNextCursor(t *testing.T) {
+	if got := (Result{}).NextCursor(); got != "" {
+		t.Errorf("last page NextCursor = %q, want empty", got)
+	}
+	next := Cursor{Time: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), ID: 3}
+	if got := (Result{Next: &next}).NextCursor(); got != next.Encode() {
+		t.Errorf("NextCursor = %q, want %q", got, next.Encode())
+	}
+}
@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/mailer"
+	"github.com/hibiken/asynq"
+)
+
+// Dispatcher enqueues tasks on behalf of the API and of other task handlers.
+type Dispatcher struct {
+	client *asynq.Client
+}
+
+func NewDispatcher(client *asynq.Client) *Dispatcher {
+	return &Dispatcher{client: client}
+}
+
+// SendEmail queues an email for the worker to deliver.
+func (d *Dispatcher) SendEmail(ctx context.Context, msg mailer.Message) error {
+	return d.enqueueEmail(ctx, EmailPayload{To: msg.To, Subject: msg.Subject, Body: msg.Body})
+}
+
+// SendUrgentEmail queues an email on the critical queue, ahead of bulk mail
+// such as reminders.
+func (d *Dispatcher) SendUrgentEmail(ctx context.Context, msg mailer.Message) error {
+	return d.enqueueEmail(ctx, EmailPayload{To: msg.To, Subject: msg.Subject, Body: msg.Body}, asynq.Queue("critical"))
+}
+
+func (d *Dispatcher) enqueueEmail(ctx context.Context, payload EmailPayload, opts ...asynq.Option) error {
+	task, err := NewEmailTask(payload)
+	if err != nil {
+		return err
+	}
+	opts = append([]asynq.Option{asynq.Queue("default"), asynq.MaxRetry(5)}, opts...)
+	_, err = d.client.EnqueueContext(ctx, task, opts...)
+	return err
+}
+
+// GenerateCertificateDocument queues rendering of a certificate's PDF. It
+// takes no task options, so the product module can queue documents without
+// depending on asynq.
+func (d *Dispatcher) GenerateCertificateDocument(ctx context.Context, certificateID int64) error {
+	task, err := NewCertificateDocumentTask(CertificateDocumentPayload{CertificateID: certificateID})
+	if err != nil {
+		return err
+	}
+	_, err = d.client.EnqueueContext(ctx, task, asynq.Queue("default"), asynq.MaxRetry(5))
+	return err
+}
+
+// ProcessImport queues an import job. Imports hold one transaction for the
+// whole file, so they are not retried as eagerly as emails.
+func (d *Dispatcher) ProcessImport(ctx context.Context, jobID int64) error {
+	return d.enqueueImport(ctx, jobID)
+}
+
+// ProcessImportAt queues an import job to run no earlier than at.
+func (d *Dispatcher) ProcessImportAt(ctx context.Context, jobID int64, at time.Time) error {
+	return d.enqueueImport(ctx, jobID, asynq.ProcessAt(at))
+}
+
+func (d *Dispatcher) enqueueImport(ctx context.Context, jobID int64, opts ...asynq.Option) error {
+	task, err := NewImportTask(ImportPayload{JobID: jobID})
+	if err != nil {
+		return err
+	}
+	opts = append([]asynq.Option{asynq.Queue("default"), asynq.MaxRetry(3), asynq.Timeout(30 * time.Minute)}, opts...)
+	_, err = d.client.EnqueueContext(ctx, task, opts...)
+	return err
+}
+
+// GenerateImageDerivatives queues resizing of a stored image.
+func (d *Dispatcher) GenerateImageDerivatives(ctx context.Context, objectName string) error {
+	task, err := NewImageDerivativesTask(ImageDerivativesPayload{Object: objectName})
+	if err != nil {
+		return err
+	}
+	_, err = d.client.EnqueueContext(ctx, task, asynq.Queue("default"), asynq.MaxRetry(3))
+	return err
+}
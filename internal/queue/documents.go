@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	productservice "github.com/Nassabiq/gpci-compro-api/internal/modules/product/service"
+	uploadsdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/certpdf"
+	"github.com/hibiken/asynq"
+)
+
+// certificateDocumentModule is the uploads module key rendered certificate
+// PDFs are stored under. It is private, so the PDFs are only served to users
+// allowed to read documents.
+const certificateDocumentModule = "document"
+
+// CertificateDocuments loads certificates and records their rendered PDF.
+type CertificateDocuments interface {
+	GetByID(ctx context.Context, certificateID int64) (productdomain.ProgramCertificate, error)
+	AttachDocument(ctx context.Context, record productdomain.ProgramCertificate, documentFile string) error
+}
+
+// DocumentStorage stores generated files in the uploads bucket.
+type DocumentStorage interface {
+	UploadBytes(ctx context.Context, module, filename string, data []byte) (uploadsdomain.UploadResult, error)
+}
+
+// CertificateDocumentHandler renders certificate PDFs.
+type CertificateDocumentHandler struct {
+	Logger    *slog.Logger
+	Documents CertificateDocuments
+	Storage   DocumentStorage
+}
+
+// ProcessTask renders the certificate PDF from the
+// program template, stores it and sets document_file. Certificates that were
+// deleted or are no longer valid by the time the task runs are skipped, and
+// so is a PDF whose certificate changed while it was rendered; the stored
+// file is then left for the uploads cleanup.
+func (h *CertificateDocumentHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	var p CertificateDocumentPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("decode certificate document payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	record, err := h.Documents.GetByID(c, p.CertificateID)
+	if errors.Is(err, productservice.ErrCertificateNotFound) {
+		h.Logger.Warn("certificate document skipped", "certificate_id", p.CertificateID, "reason", "certificate not found")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if record.Status == nil || !productdomain.CanIssueDocument(record.Status.Code) {
+		h.Logger.Warn("certificate document skipped", "certificate_id", p.CertificateID, "reason", "certificate is not valid")
+		return nil
+	}
+
+	data, err := certpdf.Render(certpdf.Certificate{
+		ProgramCode:   record.Program.Code,
+		ProgramName:   record.Program.Name,
+		CertificateNo: record.CertificateNo,
+		Certification: record.Certification.Name,
+		Product:       record.Product.Name,
+		Brand:         record.Brand.Name,
+		Company:       record.Company.Name,
+		IssueDate:     record.IssueDate,
+		ExpiryDate:    record.ExpiryDate,
+	})
+	if errors.Is(err, certpdf.ErrMissingNumber) {
+		return fmt.Errorf("certificate %d: %v: %w", p.CertificateID, err, asynq.SkipRetry)
+	}
+	if err != nil {
+		return err
+	}
+
+	stored, err := h.Storage.UploadBytes(c, certificateDocumentModule, record.CertificateNo+".pdf", data)
+	if err != nil {
+		return err
+	}
+	documentFile := path.Join(stored.Directory, stored.Filename)
+	err = h.Documents.AttachDocument(c, record, documentFile)
+	if errors.Is(err, productservice.ErrCertificateChanged) {
+		h.Logger.Warn("certificate document skipped", "certificate_id", record.ID, "reason", "certificate changed while rendering")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	h.Logger.Info("certificate document generated", "certificate_id", record.ID, "document_file", documentFile)
+	return nil
+}
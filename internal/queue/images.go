@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+
+	uploadsdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/uploads/domain"
+	"github.com/hibiken/asynq"
+)
+
+// ImageDerivatives writes the resized copies of a stored image.
+type ImageDerivatives interface {
+	GenerateDerivatives(ctx context.Context, objectName string) error
+}
+
+// ImageDerivativesHandler resizes uploaded images.
+type ImageDerivativesHandler struct {
+	Logger *slog.Logger
+	Images ImageDerivatives
+}
+
+// ProcessTask resizes an uploaded image. Images deleted
+// before the task runs are skipped, and images that cannot be decoded are not
+// retried.
+func (h *ImageDerivativesHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	var p ImageDerivativesPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("decode image derivatives payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	err := h.Images.GenerateDerivatives(c, p.Object)
+	switch {
+	case errors.Is(err, uploadsdomain.ErrObjectNotFound):
+		h.Logger.Warn("image derivatives skipped", "object", p.Object, "reason", "object not found")
+		return nil
+	case errors.Is(err, image.ErrFormat), errors.Is(err, uploadsdomain.ErrImageTooLarge):
+		return fmt.Errorf("image %s: %v: %w", p.Object, err, asynq.SkipRetry)
+	case err != nil:
+		return err
+	}
+	h.Logger.Info("image derivatives generated", "object", p.Object)
+	return nil
+}
@@ -0,0 +1,45 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/hibiken/asynq"
+)
+
+// ImportRunner processes product import jobs.
+type ImportRunner interface {
+	Process(ctx context.Context, jobID int64) error
+	Fail(ctx context.Context, jobID int64, cause error) error
+}
+
+// ImportHandler runs product import jobs.
+type ImportHandler struct {
+	Logger  *slog.Logger
+	Imports ImportRunner
+}
+
+// ProcessTask runs an import job. When the last retry fails the job
+// is marked failed so pollers stop waiting for it.
+func (h *ImportHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	var p ImportPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("decode import payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	err := h.Imports.Process(c, p.JobID)
+	if err == nil {
+		h.Logger.Info("import processed", "job_id", p.JobID)
+		return nil
+	}
+	retried, _ := asynq.GetRetryCount(c)
+	maxRetry, _ := asynq.GetMaxRetry(c)
+	if retried >= maxRetry {
+		if failErr := h.Imports.Fail(context.WithoutCancel(c), p.JobID, err); failErr != nil {
+			h.Logger.Error("mark import failed", "job_id", p.JobID, "err", failErr)
+		}
+	}
+	return err
+}
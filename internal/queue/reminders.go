@@ -0,0 +1,183 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	productdomain "github.com/Nassabiq/gpci-compro-api/internal/modules/product/domain"
+	"github.com/hibiken/asynq"
+)
+
+// reminderRetention keeps reminder email tasks around after completion so a
+// re-enqueue of the same reminder hits a task ID conflict instead of sending
+// the email twice.
+const reminderRetention = 48 * time.Hour
+
+// CertificateReminders lists certificate expiry reminders that are due and
+// tracks which of them were already sent.
+type CertificateReminders interface {
+	DueExpiryReminders(ctx context.Context, runDate time.Time, daysBefore []int) ([]productdomain.ExpiryReminder, error)
+	ClaimExpiryReminder(ctx context.Context, reminder productdomain.ExpiryReminder) (bool, error)
+	ReleaseExpiryReminder(ctx context.Context, reminder productdomain.ExpiryReminder) error
+}
+
+// RoleDirectory resolves the email addresses of users holding a role.
+type RoleDirectory interface {
+	ListRoleMemberEmails(ctx context.Context, roleName string) ([]string, error)
+}
+
+// CertificateReminderHandler emails certificate expiry reminders to the
+// certificate's company and to the admins.
+type CertificateReminderHandler struct {
+	Logger     *slog.Logger
+	Location   *time.Location
+	Reminders  CertificateReminders
+	Admins     RoleDirectory
+	Dispatcher *Dispatcher
+	Days       []int
+	// AdminRole names the role whose members get the admin reminders.
+	AdminRole string
+}
+
+// ProcessTask claims each due reminder before enqueueing its emails, and
+// releases the claim when enqueueing fails so the next run picks it up again.
+// Claimed reminders are never sent twice.
+func (h *CertificateReminderHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	runDate := scheduleNow(h.Location)
+	reminders, err := h.Reminders.DueExpiryReminders(c, runDate, h.Days)
+	if err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	admins, err := h.Admins.ListRoleMemberEmails(c, h.AdminRole)
+	if err != nil {
+		return err
+	}
+
+	var sent, failed int
+	for _, reminder := range reminders {
+		claimed, err := h.Reminders.ClaimExpiryReminder(c, reminder)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := h.enqueueReminder(c, reminder, runDate, admins); err != nil {
+			failed++
+			h.Logger.Error("enqueue certificate reminder", "certificate_id", reminder.CertificateID, "days_before", reminder.DaysBefore, "err", err)
+			if releaseErr := h.Reminders.ReleaseExpiryReminder(c, reminder); releaseErr != nil {
+				h.Logger.Error("release certificate reminder", "certificate_id", reminder.CertificateID, "err", releaseErr)
+			}
+			continue
+		}
+		sent++
+	}
+
+	h.Logger.Info("certificate reminders queued", "run_date", runDate.Format(time.DateOnly), "sent", sent, "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("%d certificate reminders could not be queued", failed)
+	}
+	return nil
+}
+
+func (h *CertificateReminderHandler) enqueueReminder(ctx context.Context, reminder productdomain.ExpiryReminder, runDate time.Time, admins []string) error {
+	today := time.Date(runDate.Year(), runDate.Month(), runDate.Day(), 0, 0, 0, 0, time.UTC)
+	daysLeft := int(reminder.ExpiryDate.Sub(today).Hours() / 24)
+	key := fmt.Sprintf("cert-reminder:%d:%d:%s", reminder.CertificateID, reminder.DaysBefore, reminder.ExpiryDate.Format(time.DateOnly))
+
+	if reminder.CompanyEmail != "" {
+		subject, body := companyReminderEmail(reminder, daysLeft)
+		if err := h.sendReminder(ctx, key+":company", []string{reminder.CompanyEmail}, subject, body); err != nil {
+			return err
+		}
+	}
+	if len(admins) > 0 {
+		subject, body := adminReminderEmail(reminder, daysLeft)
+		if err := h.sendReminder(ctx, key+":admins", admins, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendReminder enqueues one reminder email under taskID; an email already
+// enqueued under it is not sent again.
+func (h *CertificateReminderHandler) sendReminder(ctx context.Context, taskID string, to []string, subject, body string) error {
+	err := h.Dispatcher.enqueueEmail(ctx,
+		EmailPayload{To: to, Subject: subject, Body: body},
+		asynq.TaskID(taskID),
+		asynq.Retention(reminderRetention),
+	)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return err
+	}
+	return nil
+}
+
+func reminderCertificateNo(reminder productdomain.ExpiryReminder) string {
+	if reminder.CertificateNo == "" {
+		return "(no number)"
+	}
+	return reminder.CertificateNo
+}
+
+func reminderWhen(daysLeft int) string {
+	switch daysLeft {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	}
+	return fmt.Sprintf("in %d days", daysLeft)
+}
+
+// companyReminderEmail is the reminder addressed to the certificate holder.
+func companyReminderEmail(reminder productdomain.ExpiryReminder, daysLeft int) (string, string) {
+	certificateNo := reminderCertificateNo(reminder)
+	when := reminderWhen(daysLeft)
+
+	subject := fmt.Sprintf("%s certificate %s expires %s", reminder.ProgramName, certificateNo, when)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Dear %s,\n\n", reminder.CompanyName)
+	fmt.Fprintf(&body, "The %s certificate below expires %s, on %s.\n\n", reminder.ProgramName, when, reminder.ExpiryDate.Format("2 January 2006"))
+	fmt.Fprintf(&body, "Certificate number: %s\n", certificateNo)
+	fmt.Fprintf(&body, "Certification: %s\n", reminder.CertificationName)
+	fmt.Fprintf(&body, "Product: %s\n\n", reminder.ProductName)
+	body.WriteString("Please contact GPCI to arrange the renewal before the expiry date.\n")
+	return subject, body.String()
+}
+
+// adminReminderEmail is the reminder sent to GPCI admins so they can follow
+// up the renewal with the company.
+func adminReminderEmail(reminder productdomain.ExpiryReminder, daysLeft int) (string, string) {
+	certificateNo := reminderCertificateNo(reminder)
+	when := reminderWhen(daysLeft)
+
+	subject := fmt.Sprintf("Renewal due: %s %s certificate %s expires %s", reminder.CompanyName, reminder.ProgramName, certificateNo, when)
+
+	companyEmail := reminder.CompanyEmail
+	if companyEmail == "" {
+		companyEmail = "(none on file, the company was not notified)"
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "The %s certificate below expires %s, on %s.\n\n", reminder.ProgramName, when, reminder.ExpiryDate.Format("2 January 2006"))
+	fmt.Fprintf(&body, "Certificate ID: %d\n", reminder.CertificateID)
+	fmt.Fprintf(&body, "Certificate number: %s\n", certificateNo)
+	fmt.Fprintf(&body, "Certification: %s\n", reminder.CertificationName)
+	fmt.Fprintf(&body, "Product: %s\n", reminder.ProductName)
+	fmt.Fprintf(&body, "Company: %s\n", reminder.CompanyName)
+	fmt.Fprintf(&body, "Company email: %s\n\n", companyEmail)
+	body.WriteString("Please follow up the renewal with the company before the expiry date.\n")
+	return subject, body.String()
+}
@@ -1,15 +1,44 @@
 package queue
 
 import (
+	"time"
+
+	"github.com/Nassabiq/gpci-compro-api/internal/config"
 	"github.com/hibiken/asynq"
 )
 
-func NewScheduler(redisOpt asynq.RedisClientOpt) *asynq.Scheduler {
-	return asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{})
+func NewScheduler(redisOpt asynq.RedisClientOpt, loc *time.Location) *asynq.Scheduler {
+	return asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{Location: loc})
 }
 
-func RegisterSchedules(s *asynq.Scheduler) error {
-	// contoh: push heartbeat tiap menit
-	_, err := s.Register("* * * * *", asynq.NewTask("heartbeat", nil))
+// RegisterSchedules wires the periodic tasks. Unique keeps several worker
+// replicas from enqueueing the same run twice.
+func RegisterSchedules(s *asynq.Scheduler, cfg config.ScheduleConfig) error {
+	_, err := s.Register(
+		cfg.CertificateExpiryCron,
+		asynq.NewTask(TypeCertificatesExpire, nil),
+		asynq.Queue("default"),
+		asynq.Unique(time.Hour),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Register(
+		cfg.CertificateReminderCron,
+		asynq.NewTask(TypeCertificatesRemind, nil),
+		asynq.Queue("default"),
+		asynq.Unique(time.Hour),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Register(
+		cfg.UploadCleanupCron,
+		asynq.NewTask(TypeUploadsCleanup, nil),
+		asynq.Queue("default"),
+		asynq.Unique(time.Hour),
+	)
 	return err
 }
@@ -7,7 +7,14 @@ import (
 )
 
 const (
-	TypeNotifyUser = "notify:user"
+	TypeNotifyUser           = "notify:user"
+	TypeCertificatesExpire   = "certificates:expire"
+	TypeCertificatesRemind   = "certificates:remind"
+	TypeCertificatesDocument = "certificates:document"
+	TypeEmailSend            = "email:send"
+	TypeImportProcess        = "imports:process"
+	TypeUploadsDerivatives   = "uploads:derivatives"
+	TypeUploadsCleanup       = "uploads:cleanup"
 )
 
 type NotifyUserPayload struct {
@@ -15,6 +22,60 @@ type NotifyUserPayload struct {
 	Message string `json:"message"`
 }
 
+type EmailPayload struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+}
+
+// CertificateDocumentPayload identifies the product_has_certification row
+// whose PDF should be rendered.
+type CertificateDocumentPayload struct {
+	CertificateID int64 `json:"certificate_id"`
+}
+
+// ImportPayload identifies the import job to run.
+type ImportPayload struct {
+	JobID int64 `json:"job_id"`
+}
+
+// ImageDerivativesPayload names the stored image to generate derivatives for.
+type ImageDerivativesPayload struct {
+	Object string `json:"object"`
+}
+
+func NewEmailTask(p EmailPayload) (*asynq.Task, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeEmailSend, b), nil
+}
+
+func NewCertificateDocumentTask(p CertificateDocumentPayload) (*asynq.Task, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeCertificatesDocument, b), nil
+}
+
+func NewImportTask(p ImportPayload) (*asynq.Task, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeImportProcess, b), nil
+}
+
+func NewImageDerivativesTask(p ImageDerivativesPayload) (*asynq.Task, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeUploadsDerivatives, b), nil
+}
+
 func NewNotifyUserTask(p NotifyUserPayload) (*asynq.Task, error) {
 	b, err := json.Marshal(p)
 	if err != nil {
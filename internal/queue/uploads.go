@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// OrphanCleaner removes stored uploads that nothing references.
+type OrphanCleaner interface {
+	CleanupOrphans(ctx context.Context, grace time.Duration) (int, error)
+}
+
+// UploadCleanupHandler removes uploads that have stayed unreferenced for
+// longer than Grace.
+type UploadCleanupHandler struct {
+	Logger  *slog.Logger
+	Uploads OrphanCleaner
+	Grace   time.Duration
+}
+
+func (h *UploadCleanupHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	removed, err := h.Uploads.CleanupOrphans(c, h.Grace)
+	if err != nil {
+		return err
+	}
+	h.Logger.Info("orphaned uploads removed", "count", removed, "grace", h.Grace.String())
+	return nil
+}
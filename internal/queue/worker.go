@@ -3,12 +3,37 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/Nassabiq/gpci-compro-api/internal/pkg/mailer"
 	"github.com/hibiken/asynq"
 )
 
-type Handlers struct{ Logger *slog.Logger }
+// CertificateExpirer moves past-expiry certificates to expired.
+type CertificateExpirer interface {
+	ExpireDue(ctx context.Context, runDate time.Time) (int64, error)
+}
+
+// EmailSender delivers a composed email, typically over SMTP.
+type EmailSender interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+// Handlers groups the task handlers served by the worker. Each task handler
+// carries only its own dependencies; a nil one leaves its task type
+// unregistered.
+type Handlers struct {
+	Logger               *slog.Logger
+	Email                *EmailHandler
+	CertificateExpiry    *CertificateExpiryHandler
+	CertificateReminders *CertificateReminderHandler
+	CertificateDocuments *CertificateDocumentHandler
+	Imports              *ImportHandler
+	ImageDerivatives     *ImageDerivativesHandler
+	UploadCleanup        *UploadCleanupHandler
+}
 
 func (h *Handlers) NotifyUserHandler(c context.Context, t *asynq.Task) error {
 	var p NotifyUserPayload
@@ -20,6 +45,49 @@ func (h *Handlers) NotifyUserHandler(c context.Context, t *asynq.Task) error {
 	return nil
 }
 
+// EmailHandler delivers queued emails.
+type EmailHandler struct {
+	Logger *slog.Logger
+	Mailer EmailSender
+}
+
+func (h *EmailHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	var p EmailPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("decode email payload: %v: %w", err, asynq.SkipRetry)
+	}
+	if err := h.Mailer.Send(c, mailer.Message{To: p.To, Subject: p.Subject, Body: p.Body}); err != nil {
+		return err
+	}
+	h.Logger.Info("email sent", "to", p.To, "subject", p.Subject)
+	return nil
+}
+
+// CertificateExpiryHandler expires certificates past their expiry date.
+type CertificateExpiryHandler struct {
+	Logger       *slog.Logger
+	Location     *time.Location
+	Certificates CertificateExpirer
+}
+
+func (h *CertificateExpiryHandler) ProcessTask(c context.Context, t *asynq.Task) error {
+	runDate := scheduleNow(h.Location)
+	expired, err := h.Certificates.ExpireDue(c, runDate)
+	if err != nil {
+		return err
+	}
+	h.Logger.Info("certificates expired", "run_date", runDate.Format(time.DateOnly), "count", expired)
+	return nil
+}
+
+// scheduleNow returns the current time on the schedule clock.
+func scheduleNow(location *time.Location) time.Time {
+	if location == nil {
+		return time.Now().UTC()
+	}
+	return time.Now().In(location)
+}
+
 func NewServer(redisOpt asynq.RedisClientOpt, concurrency int, logger *slog.Logger) *asynq.Server {
 	return asynq.NewServer(redisOpt, asynq.Config{Concurrency: concurrency, Queues: map[string]int{"critical": 2, "default": 8}})
 }
@@ -27,5 +95,26 @@ func NewServer(redisOpt asynq.RedisClientOpt, concurrency int, logger *slog.Logg
 func NewMux(h *Handlers) *asynq.ServeMux {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TypeNotifyUser, h.NotifyUserHandler)
+	if h.Email != nil {
+		mux.Handle(TypeEmailSend, h.Email)
+	}
+	if h.CertificateExpiry != nil {
+		mux.Handle(TypeCertificatesExpire, h.CertificateExpiry)
+	}
+	if h.CertificateReminders != nil {
+		mux.Handle(TypeCertificatesRemind, h.CertificateReminders)
+	}
+	if h.CertificateDocuments != nil {
+		mux.Handle(TypeCertificatesDocument, h.CertificateDocuments)
+	}
+	if h.Imports != nil {
+		mux.Handle(TypeImportProcess, h.Imports)
+	}
+	if h.ImageDerivatives != nil {
+		mux.Handle(TypeUploadsDerivatives, h.ImageDerivatives)
+	}
+	if h.UploadCleanup != nil {
+		mux.Handle(TypeUploadsCleanup, h.UploadCleanup)
+	}
 	return mux
 }
@@ -0,0 +1,41 @@
+-- +goose Up
+INSERT INTO permissions (key, description)
+VALUES
+    ('companies.read', 'List or view companies'),
+    ('companies.write', 'Create or update companies'),
+    ('companies.delete', 'Delete companies')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key IN (
+    'companies.read',
+    'companies.write',
+    'companies.delete'
+)
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key IN (
+    'companies.read',
+    'companies.write'
+)
+WHERE r.name = 'editor'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id FROM permissions
+    WHERE key IN ('companies.read', 'companies.write', 'companies.delete')
+);
+
+DELETE FROM permissions WHERE key IN (
+    'companies.read',
+    'companies.write',
+    'companies.delete'
+);
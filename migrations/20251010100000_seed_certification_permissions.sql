@@ -0,0 +1,41 @@
+-- +goose Up
+INSERT INTO permissions (key, description)
+VALUES
+    ('certifications.read', 'List or view certification types'),
+    ('certifications.write', 'Create or update certification types'),
+    ('certifications.delete', 'Delete certification types')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key IN (
+    'certifications.read',
+    'certifications.write',
+    'certifications.delete'
+)
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key IN (
+    'certifications.read',
+    'certifications.write'
+)
+WHERE r.name = 'editor'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id FROM permissions
+    WHERE key IN ('certifications.read', 'certifications.write', 'certifications.delete')
+);
+
+DELETE FROM permissions WHERE key IN (
+    'certifications.read',
+    'certifications.write',
+    'certifications.delete'
+);
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.certificate_expiry_runs (
+    run_date DATE PRIMARY KEY,
+    expired_count INTEGER NOT NULL DEFAULT 0,
+    run_count INTEGER NOT NULL DEFAULT 0,
+    first_run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_run_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_phc_status_expiry ON public.product_has_certification (status_id, expiry_date);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_phc_status_expiry;
+DROP TABLE IF EXISTS public.certificate_expiry_runs;
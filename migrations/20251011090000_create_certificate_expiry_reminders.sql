@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.certificate_expiry_reminders (
+    id BIGSERIAL PRIMARY KEY,
+    certificate_id BIGINT NOT NULL,
+    days_before INTEGER NOT NULL,
+    expiry_date DATE NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_cer_certificate FOREIGN KEY (certificate_id) REFERENCES public.product_has_certification(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    -- expiry_date is part of the key so a renewed certificate gets fresh reminders
+    CONSTRAINT uk_cer_certificate_threshold UNIQUE (certificate_id, days_before, expiry_date)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS public.certificate_expiry_reminders;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Bumped whenever every session of a user must end (password change,
+-- deactivation, "log out everywhere"); access tokens carry it as "ver".
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.certificate_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    certificate_id BIGINT NOT NULL REFERENCES public.product_has_certification(id) ON DELETE CASCADE,
+    from_status_id SMALLINT REFERENCES public.lkp_cert_status(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    to_status_id SMALLINT NOT NULL REFERENCES public.lkp_cert_status(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    reason TEXT NOT NULL,
+    -- NULL when the worker made the change
+    actor_xid TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cert_status_history_cert ON public.certificate_status_history (certificate_id, created_at);
+
+INSERT INTO permissions (key, description)
+VALUES ('product.certifications.transition', 'Approve, suspend, reinstate or revoke product certificates')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key = 'product.certifications.transition'
+WHERE r.name IN ('admin', 'editor')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id FROM permissions WHERE key = 'product.certifications.transition'
+);
+
+DELETE FROM permissions WHERE key = 'product.certifications.transition';
+
+DROP TABLE IF EXISTS public.certificate_status_history;
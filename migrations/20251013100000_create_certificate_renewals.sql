@@ -0,0 +1,26 @@
+-- +goose Up
+-- Terms replaced by a renewal. The product_has_certification row always holds
+-- the current terms; each renewal archives the previous ones here.
+CREATE TABLE IF NOT EXISTS public.certificate_renewals (
+    id BIGSERIAL PRIMARY KEY,
+    certificate_id BIGINT NOT NULL REFERENCES public.product_has_certification(id) ON DELETE CASCADE,
+    certificate_no VARCHAR(140),
+    issue_date DATE,
+    expiry_date DATE,
+    status_id SMALLINT REFERENCES public.lkp_cert_status(id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    document_file TEXT,
+    meta_json JSONB NOT NULL DEFAULT '{}' :: jsonb,
+    renewed_by TEXT,
+    renewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_renewals_cert ON public.certificate_renewals (certificate_id, renewed_at DESC);
+
+-- Public verification looks certificate numbers up case-insensitively, first
+-- among current certificates and then among the numbers renewals replaced.
+CREATE INDEX IF NOT EXISTS idx_product_has_certification_certificate_no_upper ON public.product_has_certification (UPPER(certificate_no));
+CREATE INDEX IF NOT EXISTS idx_certificate_renewals_certificate_no_upper ON public.certificate_renewals (UPPER(certificate_no));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_has_certification_certificate_no_upper;
+DROP TABLE IF EXISTS public.certificate_renewals;
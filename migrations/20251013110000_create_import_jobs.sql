@@ -0,0 +1,51 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.import_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    filename TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    -- 'all_or_nothing' or 'partial'
+    mode VARCHAR(20) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    -- 'queued', 'running', 'completed' or 'failed'
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    succeeded_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    committed BOOLEAN NOT NULL DEFAULT FALSE,
+    rows_data JSONB NOT NULL DEFAULT '[]'::jsonb,
+    results JSONB,
+    error TEXT,
+    created_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    -- Runs that claimed the job, so a job whose runs keep dying is failed
+    -- instead of reclaimed forever
+    attempts INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_created ON public.import_jobs (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_running ON public.import_jobs (started_at) WHERE status = 'running';
+
+INSERT INTO permissions (key, description)
+VALUES
+    ('imports.read', 'View product import jobs'),
+    ('imports.write', 'Import products and certificates from CSV/XLSX files')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key IN ('imports.read', 'imports.write')
+WHERE r.name IN ('admin', 'editor')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id FROM permissions WHERE key IN ('imports.read', 'imports.write')
+);
+
+DELETE FROM permissions WHERE key IN ('imports.read', 'imports.write');
+
+DROP TABLE IF EXISTS public.import_jobs;
@@ -0,0 +1,88 @@
+-- +goose Up
+-- Names are indexed unstemmed ('simple') and stemmed for Indonesian and
+-- English, so both exact words and inflected forms match.
+ALTER TABLE public.products
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(slug, '')), 'B') ||
+        setweight(to_tsvector('indonesian', coalesce(features, '') || ' ' || coalesce(reason, '')), 'C') ||
+        setweight(to_tsvector('english', coalesce(features, '') || ' ' || coalesce(reason, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search ON public.products USING GIN (search_vector);
+
+ALTER TABLE public.brands
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(slug, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_brands_search ON public.brands USING GIN (search_vector);
+
+ALTER TABLE public.companies
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(slug, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(address, '')), 'D')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_companies_search ON public.companies USING GIN (search_vector);
+
+ALTER TABLE public.certifications
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(name, '')), 'A')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_certifications_search ON public.certifications USING GIN (search_vector);
+
+-- Certificate numbers are indexed whole and split on punctuation, so
+-- 'GL/2025/001' also matches a search for '2025 001'.
+ALTER TABLE public.product_has_certification
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('simple', coalesce(certificate_no, '')) ||
+        to_tsvector('simple', regexp_replace(coalesce(certificate_no, ''), '[^[:alnum:]]+', ' ', 'g'))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_phc_search ON public.product_has_certification USING GIN (search_vector);
+
+INSERT INTO permissions (key, description)
+VALUES ('search.read', 'Search products, brands, companies and certificates')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key = 'search.read'
+WHERE r.name IN ('admin', 'editor')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id FROM permissions WHERE key = 'search.read'
+);
+
+DELETE FROM permissions WHERE key = 'search.read';
+
+DROP INDEX IF EXISTS public.idx_phc_search;
+ALTER TABLE public.product_has_certification DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS public.idx_certifications_search;
+ALTER TABLE public.certifications DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS public.idx_companies_search;
+ALTER TABLE public.companies DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS public.idx_brands_search;
+ALTER TABLE public.brands DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS public.idx_products_search;
+ALTER TABLE public.products DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Keyset pagination walks these orderings from a (timestamp, id) cursor.
+CREATE INDEX IF NOT EXISTS idx_products_created ON public.products (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_faqs_created ON public.faqs (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_phc_updated ON public.product_has_certification (updated_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS public.idx_phc_updated;
+DROP INDEX IF EXISTS public.idx_faqs_created;
+DROP INDEX IF EXISTS public.idx_products_created;
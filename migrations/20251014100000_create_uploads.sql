@@ -0,0 +1,54 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.uploads (
+    id BIGSERIAL PRIMARY KEY,
+    -- Key of the object in the uploads bucket, e.g. uploads/images/products/2025/10/14/<xid>.jpg
+    object_key TEXT NOT NULL,
+    module VARCHAR(50) NOT NULL DEFAULT '',
+    category VARCHAR(20) NOT NULL,
+    original_filename TEXT NOT NULL,
+    mime_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    -- Hex-encoded SHA-256 of the stored content; NULL for direct uploads,
+    -- which are never read back through the API
+    checksum CHAR(64),
+    -- Object keys of the resized copies written by the worker
+    derivatives JSONB NOT NULL DEFAULT '[]'::jsonb,
+    -- User xid, NULL for files generated by the worker
+    uploaded_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_uploads_object_key UNIQUE (object_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploads_created ON public.uploads (created_at);
+-- Finds the upload a referenced derivative belongs to.
+CREATE INDEX IF NOT EXISTS idx_uploads_derivatives ON public.uploads USING GIN (derivatives);
+
+-- Every stored file reference held by an entity. Uploads matching none of
+-- them are orphans.
+CREATE OR REPLACE VIEW public.upload_references AS
+SELECT 'product' AS entity_type, p.id AS entity_id, img.value AS reference
+FROM public.products p
+CROSS JOIN LATERAL jsonb_array_elements_text(
+    CASE WHEN jsonb_typeof(p.images) = 'array' THEN p.images ELSE '[]'::jsonb END
+) AS img(value)
+UNION ALL
+SELECT 'company', c.id, c.image
+FROM public.companies c
+WHERE c.image IS NOT NULL AND c.image <> ''
+UNION ALL
+SELECT 'certification', ce.id, ce.image
+FROM public.certifications ce
+WHERE ce.image IS NOT NULL AND ce.image <> ''
+UNION ALL
+SELECT 'product_certification', phc.id, phc.document_file
+FROM public.product_has_certification phc
+WHERE phc.document_file IS NOT NULL AND phc.document_file <> ''
+UNION ALL
+SELECT 'certificate_renewal', cr.id, cr.document_file
+FROM public.certificate_renewals cr
+WHERE cr.document_file IS NOT NULL AND cr.document_file <> '';
+
+-- +goose Down
+DROP VIEW IF EXISTS public.upload_references;
+
+DROP TABLE IF EXISTS public.uploads;
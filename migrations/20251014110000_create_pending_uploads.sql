@@ -0,0 +1,22 @@
+-- +goose Up
+-- Direct uploads that were issued a presigned PUT URL but not completed yet.
+-- Completing one moves it to uploads; the cleanup task removes the rest once
+-- their URL has expired.
+CREATE TABLE IF NOT EXISTS public.pending_uploads (
+    id BIGSERIAL PRIMARY KEY,
+    object_key TEXT NOT NULL,
+    module VARCHAR(50) NOT NULL DEFAULT '',
+    category VARCHAR(20) NOT NULL,
+    original_filename TEXT NOT NULL,
+    mime_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    uploaded_by TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_pending_uploads_object_key UNIQUE (object_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_uploads_expires ON public.pending_uploads (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS public.pending_uploads;
@@ -0,0 +1,19 @@
+-- +goose Up
+INSERT INTO permissions (key, description)
+VALUES ('uploads.documents.read', 'Download private uploaded documents')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.key = 'uploads.documents.read'
+WHERE r.name IN ('admin', 'editor')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM role_permissions
+WHERE permission_id IN (
+    SELECT id FROM permissions WHERE key = 'uploads.documents.read'
+);
+
+DELETE FROM permissions WHERE key = 'uploads.documents.read';
@@ -0,0 +1,115 @@
+-- +goose Up
+-- Every object key a stored reference may name: the reference itself and
+-- each '/'-separated tail of it, so 'https://cdn/bucket/<key>' is found by an
+-- exact lookup of <key>. Triggers keep it in step with the columns listed by
+-- the upload_references view, and the uploads cleanup checks it instead of
+-- matching every reference against every candidate.
+CREATE TABLE IF NOT EXISTS public.upload_reference_keys (
+    entity_type VARCHAR(40) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    object_key TEXT NOT NULL,
+    PRIMARY KEY (entity_type, entity_id, object_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_reference_keys_key ON public.upload_reference_keys (object_key);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_reference_key_suffixes(reference TEXT)
+RETURNS SETOF TEXT
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT DISTINCT array_to_string(parts[i:], '/')
+    FROM (SELECT string_to_array(reference, '/') AS parts) p,
+         generate_series(1, coalesce(array_length(parts, 1), 0)) AS i
+    WHERE parts[i] <> ''
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Rewrites the reference keys of the changed row. The uploads they name,
+-- directly or through one of their derivatives, are locked FOR KEY SHARE, so
+-- a cleanup deleting one of them waits for this transaction and then sees the
+-- new reference.
+CREATE OR REPLACE FUNCTION public.fn_sync_upload_reference_keys()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    entity TEXT := TG_ARGV[0];
+    refs TEXT[];
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM public.upload_reference_keys WHERE entity_type = entity AND entity_id = OLD.id;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+
+    IF TG_TABLE_NAME = 'products' THEN
+        SELECT array_agg(img.value) INTO refs
+        FROM jsonb_array_elements_text(
+            CASE WHEN jsonb_typeof(NEW.images) = 'array' THEN NEW.images ELSE '[]'::jsonb END
+        ) AS img(value);
+    ELSIF TG_TABLE_NAME IN ('companies', 'certifications') THEN
+        refs := ARRAY[NEW.image];
+    ELSE
+        refs := ARRAY[NEW.document_file];
+    END IF;
+
+    INSERT INTO public.upload_reference_keys (entity_type, entity_id, object_key)
+    SELECT DISTINCT entity, NEW.id, suffix
+    FROM unnest(refs) AS r(ref), public.fn_reference_key_suffixes(r.ref) AS suffix
+    WHERE r.ref IS NOT NULL AND r.ref <> ''
+    ON CONFLICT DO NOTHING;
+
+    SELECT array_agg(k.object_key) INTO refs
+    FROM public.upload_reference_keys k
+    WHERE k.entity_type = entity AND k.entity_id = NEW.id;
+
+    PERFORM 1
+    FROM public.uploads u
+    WHERE u.object_key = ANY(refs) OR u.derivatives ?| refs
+    FOR KEY SHARE;
+
+    RETURN NEW;
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_upload_reference_keys_products
+AFTER INSERT OR UPDATE OF images OR DELETE ON public.products
+FOR EACH ROW EXECUTE FUNCTION public.fn_sync_upload_reference_keys('product');
+
+CREATE TRIGGER trg_upload_reference_keys_companies
+AFTER INSERT OR UPDATE OF image OR DELETE ON public.companies
+FOR EACH ROW EXECUTE FUNCTION public.fn_sync_upload_reference_keys('company');
+
+CREATE TRIGGER trg_upload_reference_keys_certifications
+AFTER INSERT OR UPDATE OF image OR DELETE ON public.certifications
+FOR EACH ROW EXECUTE FUNCTION public.fn_sync_upload_reference_keys('certification');
+
+CREATE TRIGGER trg_upload_reference_keys_product_certification
+AFTER INSERT OR UPDATE OF document_file OR DELETE ON public.product_has_certification
+FOR EACH ROW EXECUTE FUNCTION public.fn_sync_upload_reference_keys('product_certification');
+
+CREATE TRIGGER trg_upload_reference_keys_certificate_renewals
+AFTER INSERT OR UPDATE OF document_file OR DELETE ON public.certificate_renewals
+FOR EACH ROW EXECUTE FUNCTION public.fn_sync_upload_reference_keys('certificate_renewal');
+
+INSERT INTO public.upload_reference_keys (entity_type, entity_id, object_key)
+SELECT DISTINCT r.entity_type, r.entity_id, suffix
+FROM public.upload_references r, public.fn_reference_key_suffixes(r.reference) AS suffix
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_upload_reference_keys_certificate_renewals ON public.certificate_renewals;
+DROP TRIGGER IF EXISTS trg_upload_reference_keys_product_certification ON public.product_has_certification;
+DROP TRIGGER IF EXISTS trg_upload_reference_keys_certifications ON public.certifications;
+DROP TRIGGER IF EXISTS trg_upload_reference_keys_companies ON public.companies;
+DROP TRIGGER IF EXISTS trg_upload_reference_keys_products ON public.products;
+
+DROP FUNCTION IF EXISTS public.fn_sync_upload_reference_keys();
+DROP FUNCTION IF EXISTS public.fn_reference_key_suffixes(TEXT);
+
+DROP TABLE IF EXISTS public.upload_reference_keys;